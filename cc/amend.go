@@ -0,0 +1,104 @@
+package cc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// amendTarget is the existing commit whose message is being rewritten.
+type amendTarget struct {
+	sha    string
+	parent string
+	head   bool
+}
+
+// splitAmendArgs splits the arguments of the amend command into the revision of the
+// commit to amend, HEAD unless the first argument is not a parameter, and the
+// parameters that are parsed the same way as for a new commit.
+func splitAmendArgs(args []string) (string, []string) {
+	if len(args) == 0 {
+		return "HEAD", args
+	}
+
+	for _, p := range ccParams {
+		if args[0] == p {
+			return "HEAD", args
+		}
+	}
+	return args[0], args[1:]
+}
+
+// readCommit parses the message of the commit at rev back into the fields of the CC,
+// so that every prompt is pre-filled with its current value. Messages that do not
+// follow the conventional commit format are filled in as far as they can be parsed.
+func (c *CLI) readCommit(rev string) error {
+	sha, err := c.ce.output("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		fmt.Fprintf(c.Out, "Not a commit: %s\n", rev)
+		return &CCError{"Not a commit"}
+	}
+
+	if _, err := c.ce.output("merge-base", "--is-ancestor", sha, "HEAD"); err != nil {
+		fmt.Fprintf(c.Out, "Commit %s is not in the history of HEAD\n", rev)
+		return &CCError{"Commit not in history"}
+	}
+
+	message, err := c.ce.output("log", "-1", "--format=%B", sha)
+	if err != nil {
+		fmt.Fprintf(c.Out, "Error reading message of %s: %s\n", rev, err)
+		return err
+	}
+
+	cc, err := Parse(c.cfg.untemplate(message))
+	var formatErr *FormatError
+	if errors.As(err, &formatErr) {
+		fmt.Fprintf(c.Out, "Commit %s does not follow the conventional commit format:\n", rev)
+		for _, err := range formatErr.Errors {
+			fmt.Fprintf(c.Out, "  %s\n", err)
+		}
+	}
+	c.cc = &cc
+
+	head, _ := c.ce.output("rev-parse", "HEAD")
+	parent, _ := c.ce.output("rev-parse", "--verify", "--quiet", sha+"^")
+	c.amending = &amendTarget{sha: sha, parent: parent, head: sha == head}
+	return nil
+}
+
+// amendCommit replaces the message of the commit being amended with the message
+// that was built. The last commit is amended directly, while an older commit is
+// reworded with an amend! commit that an automated rebase squashes into it.
+func (c *CLI) amendCommit() error {
+	if c.amending.head {
+		return c.runGit(c.ce.amend(c.message, c.signed), "amending commit")
+	}
+
+	short := shortSHA(c.amending.sha)
+
+	cmd := c.ce.fixup("amend! "+c.amending.sha+"\n\n"+c.message, c.signed)
+	if err := c.runGit(cmd, "committing the new message of "+short); err != nil {
+		return err
+	}
+
+	cmd = c.ce.autosquash(c.amending.parent, c.signed)
+	if err := c.runGit(cmd, "rebasing to reword "+short); err != nil {
+		fmt.Fprintln(c.Out, "Resolve the rebase, or run git rebase --abort and drop the amend! commit")
+		return err
+	}
+	return nil
+}
+
+// withCurrent adds the current value of a field to a prompt, as in
+// `Enter a subject [add lint]: `, so that the value can be kept by entering nothing.
+// Only the first line of values that span several lines is shown.
+func withCurrent(prompt, value string) string {
+	if value == "" {
+		return prompt
+	}
+
+	if first, _, ok := strings.Cut(value, "\n"); ok {
+		value = first + "..."
+	}
+	return strings.TrimSuffix(prompt, ": ") + " [" + value + "]: "
+}
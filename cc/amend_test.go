@@ -0,0 +1,120 @@
+package cc
+
+import (
+	"testing"
+)
+
+func TestSplitAmendArgs(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		args   []string
+		rev    string
+		params int
+	}{
+		{desc: "No arguments", args: nil, rev: "HEAD", params: 0},
+		{desc: "Revision", args: []string{"HEAD~2"}, rev: "HEAD~2", params: 0},
+		{desc: "Parameters only", args: []string{"scope", "lint", "yes"}, rev: "HEAD", params: 3},
+		{desc: "Revision and parameters", args: []string{"abc123", "yes"}, rev: "abc123", params: 1},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			rev, params := splitAmendArgs(tC.args)
+
+			if rev != tC.rev || len(params) != tC.params {
+				t.Errorf("got %q %v want %q with %d params", rev, params, tC.rev, tC.params)
+			}
+		})
+	}
+}
+
+// amendOutputs are the git outputs of amending HEAD~1, the commit aaa whose parent
+// is bbb, with HEAD at ccc.
+var amendOutputs = map[string]string{
+	"rev-parse --verify --quiet HEAD~1^{commit}": "aaa",
+	"merge-base --is-ancestor aaa HEAD":          "",
+	"log -1 --format=%B aaa":                     "feat(lnt): add lint\n\nChecks messages.\n\nRefs: #1",
+	"rev-parse HEAD":                             "ccc",
+	"rev-parse --verify --quiet aaa^":            "bbb",
+}
+
+func TestAmend(t *testing.T) {
+	t.Run("Prompts pre-filled with current values", func(t *testing.T) {
+		buffer, cli, ce := mockGitCLI(amendOutputs, "", "lint", "", "-", "Closes #2", "", "y")
+		ce.outputs["rev-parse --verify --quiet HEAD^{commit}"] = "aaa"
+		ce.outputs["rev-parse HEAD"] = "aaa"
+
+		if err := cli.readCommit("HEAD"); err != nil {
+			t.Fatal(err)
+		}
+		buffer.Reset()
+
+		cli.readMissingFields()
+		cli.buildMessage()
+		cli.makeCommit()
+
+		got := buffer.String()[len(cli.cfg.typesPrompt())-2:]
+		wantPrompts := " [feat]: Enter a scope [lnt]: Enter a subject [add lint]: Enter a body [Checks messages.]: " +
+			"Enter a footer [Refs: #1]: Enter a footer: "
+		if got[:len(wantPrompts)] != wantPrompts {
+			t.Errorf("got %q want %q", got[:len(wantPrompts)], wantPrompts)
+		}
+
+		want := "feat(lint): add lint\n\nRefs: #1\nCloses: #2"
+		if cli.message != want {
+			t.Errorf("got %q want %q", cli.message, want)
+		}
+
+		if ce.command != "amend" {
+			t.Errorf("got %q want %q", ce.command, "amend")
+		}
+	})
+
+	t.Run("Older commit reworded with amend! commit", func(t *testing.T) {
+		_, cli, ce := mockGitCLI(amendOutputs)
+
+		if err := cli.readCommit("HEAD~1"); err != nil {
+			t.Fatal(err)
+		}
+		cli.parseParams([]string{"scope", "lint", "body", "", "yes"})
+		cli.readMissingFields()
+		cli.buildMessage()
+		cli.makeCommit()
+
+		if cli.amending.head || cli.amending.parent != "bbb" {
+			t.Errorf("got %+v want an older commit with parent bbb", cli.amending)
+		}
+
+		if ce.command != "autosquash" {
+			t.Errorf("got %q want %q", ce.command, "autosquash")
+		}
+	})
+
+	t.Run("Commit outside of history refused", func(t *testing.T) {
+		_, cli, ce := mockGitCLI(amendOutputs)
+		delete(ce.outputs, "merge-base --is-ancestor aaa HEAD")
+
+		if err := cli.readCommit("HEAD~1"); err == nil {
+			t.Errorf("got no error want an error")
+		}
+	})
+}
+
+func TestWithCurrent(t *testing.T) {
+	testCases := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: "Enter a body: "},
+		{value: "short body", want: "Enter a body [short body]: "},
+		{value: "first line\nsecond line", want: "Enter a body [first line...]: "},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.want, func(t *testing.T) {
+			got := withCurrent("Enter a body: ", tC.value)
+
+			if got != tC.want {
+				t.Errorf("got %q want %q", got, tC.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package cc
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// ticketToken is the token of the footer referencing the ticket in the branch name.
+const ticketToken = "Refs"
+
+// branchRule extracts the fields of a conventional commit from the name of the
+// current branch, with the named groups type, scope and ticket of Pattern.
+type branchRule struct {
+	Pattern string `yaml:"pattern"`
+}
+
+// defaultBranches match branches named like feat/PROJ-123-short-desc and
+// fix/payments/short-desc.
+var defaultBranches = []branchRule{
+	{Pattern: `^(?P<type>[a-z]+)/(?:(?P<scope>[a-z0-9-]+)/)?(?P<ticket>[A-Z][A-Z0-9]*-[0-9]+)?`},
+}
+
+// branchFields are the fields of a conventional commit taken from a branch name.
+type branchFields struct {
+	cctype string
+	scope  string
+	ticket string
+}
+
+// parseBranch returns the fields extracted from branch by the first branches rule
+// that matches it. A type that is not configured, or a scope that is not allowed,
+// is left out.
+func (cfg *config) parseBranch(branch string) (branchFields, error) {
+	for _, rule := range cfg.Branches {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return branchFields{}, fmt.Errorf("invalid branches pattern %q: %w", rule.Pattern, err)
+		}
+
+		match := re.FindStringSubmatch(branch)
+		if match == nil {
+			continue
+		}
+
+		group := func(name string) string {
+			if i := re.SubexpIndex(name); i >= 0 {
+				return match[i]
+			}
+			return ""
+		}
+
+		fields := branchFields{cctype: group("type"), scope: group("scope"), ticket: group("ticket")}
+		if !cfg.isType(fields.cctype) {
+			fields.cctype = ""
+		}
+		if !cfg.isScope(fields.scope) {
+			fields.scope = ""
+		}
+		return fields, nil
+	}
+	return branchFields{}, nil
+}
+
+// readBranch pre-fills the type, the scope and a Refs footer for the ticket from
+// the name of the current branch, as the current values of the prompts so that each
+// can still be overridden. Fields passed as parameters are kept.
+func (c *CLI) readBranch() {
+	branch, err := c.ce.output("symbolic-ref", "--short", "HEAD")
+	if err != nil || branch == "" {
+		return
+	}
+
+	fields, err := c.cfg.parseBranch(branch)
+	if err != nil {
+		fmt.Fprintln(c.Out, err)
+		return
+	}
+
+	if fields.cctype != "" && !c.params["type"] {
+		c.cc.Type = fields.cctype
+	}
+	if fields.scope != "" && !c.params["scope"] {
+		c.cc.Scope = fields.scope
+	}
+	if fields.ticket != "" && !c.params["footer"] {
+		c.cc.Footers = append(c.cc.Footers, Trailer{Token: ticketToken, Value: fields.ticket}.gitTrailer())
+	}
+}
@@ -0,0 +1,87 @@
+package cc
+
+import (
+	"testing"
+)
+
+func TestParseBranch(t *testing.T) {
+	testCases := []struct {
+		branch string
+		want   branchFields
+	}{
+		{branch: "feat/PROJ-123-short-desc", want: branchFields{cctype: "feat", ticket: "PROJ-123"}},
+		{branch: "fix/payments/round-totals", want: branchFields{cctype: "fix", scope: "payments"}},
+		{branch: "fix/payments/PAY-9-round-totals", want: branchFields{cctype: "fix", scope: "payments", ticket: "PAY-9"}},
+		{branch: "feature/add-picker", want: branchFields{}},
+		{branch: "main", want: branchFields{}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.branch, func(t *testing.T) {
+			got, err := defaultConfig().parseBranch(tC.branch)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tC.want {
+				t.Errorf("got %+v want %+v", got, tC.want)
+			}
+		})
+	}
+
+	t.Run("Configured patterns", func(t *testing.T) {
+		cfg := defaultConfig()
+		cfg.Branches = []branchRule{
+			{Pattern: `^(?P<ticket>[0-9]+)-`},
+			{Pattern: `^users/[a-z]+/(?P<scope>[a-z]+)`},
+		}
+
+		got, _ := cfg.parseBranch("users/ada/lint")
+		if want := (branchFields{scope: "lint"}); got != want {
+			t.Errorf("got %+v want %+v", got, want)
+		}
+
+		cfg.Branches = []branchRule{{Pattern: `(?P<type>`}}
+		if _, err := cfg.parseBranch("feat/x"); err == nil {
+			t.Errorf("got no error want invalid pattern")
+		}
+	})
+}
+
+func TestReadBranch(t *testing.T) {
+	t.Run("Fields pre-filled", func(t *testing.T) {
+		_, cli, _ := mockGitCLI(map[string]string{"symbolic-ref --short HEAD": "feat/PROJ-123-short-desc"}, "", "", "add branches", "", "", "y")
+
+		cli.readBranch()
+		cli.readMissingFields()
+		cli.buildMessage()
+
+		want := "feat: add branches\n\nRefs: PROJ-123"
+		if cli.message != want {
+			t.Errorf("got %q want %q", cli.message, want)
+		}
+	})
+
+	t.Run("Fields overridden", func(t *testing.T) {
+		_, cli, _ := mockGitCLI(map[string]string{"symbolic-ref --short HEAD": "feat/payments/PROJ-123"}, "4", "cc", "add branches", "", "-", "")
+
+		cli.readBranch()
+		cli.readMissingFields()
+		cli.buildMessage()
+
+		want := "fix(cc): add branches"
+		if cli.message != want {
+			t.Errorf("got %q want %q", cli.message, want)
+		}
+	})
+
+	t.Run("Parameters kept", func(t *testing.T) {
+		_, cli, _ := mockGitCLI(map[string]string{"symbolic-ref --short HEAD": "feat/payments/PROJ-123"})
+		cli.parseParams([]string{"type", "docs", "footer", "Closes #1"})
+
+		cli.readBranch()
+
+		if cli.cc.Type != "docs" || cli.cc.Scope != "payments" || len(cli.cc.Footers) != 1 {
+			t.Errorf("got %+v want the type and footer of the parameters", cli.cc)
+		}
+	})
+}
@@ -0,0 +1,286 @@
+package cc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Levels of a semantic version bump, in increasing order.
+const (
+	bumpNone = iota
+	bumpPatch
+	bumpMinor
+	bumpMajor
+)
+
+// semverRegex matches a semantic version with an optional v prefix and prerelease
+// suffix, such as v1.2.0 or 1.2.0-rc.1.
+var semverRegex = regexp.MustCompile(`^(v?)(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?$`)
+
+// semver is a semantic version.
+type semver struct {
+	prefix string
+	major  int
+	minor  int
+	patch  int
+	pre    string
+}
+
+// parseSemver parses a tag such as v1.2.0 or 1.2.0-rc.1 into a semver.
+func parseSemver(tag string) (semver, bool) {
+	m := semverRegex.FindStringSubmatch(tag)
+	if m == nil {
+		return semver{}, false
+	}
+
+	major, _ := strconv.Atoi(m[2])
+	minor, _ := strconv.Atoi(m[3])
+	patch, _ := strconv.Atoi(m[4])
+	return semver{prefix: m[1], major: major, minor: minor, patch: patch, pre: m[5]}, true
+}
+
+// String returns the version in the same form it was parsed from.
+func (v semver) String() string {
+	s := fmt.Sprintf("%s%d.%d.%d", v.prefix, v.major, v.minor, v.patch)
+	if v.pre != "" {
+		s += "-" + v.pre
+	}
+	return s
+}
+
+// release returns the version without its prerelease suffix.
+func (v semver) release() semver {
+	v.pre = ""
+	return v
+}
+
+// preNumber splits a prerelease suffix such as rc.2 into its identifier and number.
+func (v semver) preNumber() (string, int) {
+	i := strings.LastIndex(v.pre, ".")
+	if i < 0 {
+		return v.pre, 0
+	}
+	n, err := strconv.Atoi(v.pre[i+1:])
+	if err != nil {
+		return v.pre, 0
+	}
+	return v.pre[:i], n
+}
+
+// less reports whether v has a lower precedence than o. A prerelease has a lower
+// precedence than its release.
+func (v semver) less(o semver) bool {
+	if v.major != o.major {
+		return v.major < o.major
+	}
+	if v.minor != o.minor {
+		return v.minor < o.minor
+	}
+	if v.patch != o.patch {
+		return v.patch < o.patch
+	}
+	if v.pre == "" || o.pre == "" {
+		return v.pre != "" && o.pre == ""
+	}
+
+	vID, vN := v.preNumber()
+	oID, oN := o.preNumber()
+	if vID != oID {
+		return vID < oID
+	}
+	return vN < oN
+}
+
+// bump returns the version incremented by level. Before 1.0.0, breaking changes
+// increment the minor version instead of the major version.
+func (v semver) bump(level int) semver {
+	if v.major == 0 && level == bumpMajor {
+		level = bumpMinor
+	}
+
+	switch level {
+	case bumpMajor:
+		return semver{prefix: v.prefix, major: v.major + 1}
+	case bumpMinor:
+		return semver{prefix: v.prefix, major: v.major, minor: v.minor + 1}
+	case bumpPatch:
+		return semver{prefix: v.prefix, major: v.major, minor: v.minor, patch: v.patch + 1}
+	}
+	return v
+}
+
+// bumpLevel returns the level the version has to be bumped by for the commits:
+// major for breaking changes, minor for features and patch for fixes.
+func bumpLevel(commits []commit) int {
+	level := bumpNone
+	for _, cm := range commits {
+		switch {
+		case cm.isBreaking():
+			return bumpMajor
+		case cm.cc.Type == "feat":
+			level = bumpMinor
+		case cm.cc.Type == "fix" && level < bumpPatch:
+			level = bumpPatch
+		}
+	}
+	return level
+}
+
+// nextVersion returns the version that follows the latest release for the given bump
+// level. With a prerelease identifier the next prerelease of that version is returned
+// instead, numbered after the latest existing prerelease with the same identifier.
+func nextVersion(latest semver, versions []semver, level int, preID string) semver {
+	next := latest.bump(level)
+	if preID == "" {
+		return next
+	}
+
+	n := 0
+	for _, v := range versions {
+		if v.release() != next.release() || v.pre == "" {
+			continue
+		}
+		if id, vn := v.preNumber(); id == preID && vn > n {
+			n = vn
+		}
+	}
+
+	next.pre = preID + "." + strconv.Itoa(n+1)
+	return next
+}
+
+// prereleaseLine returns the version of the highest prerelease tagged after the
+// latest release, such as 1.0.0 for 1.0.0-rc.1, which the next version continues
+// or releases when the commits do not call for a higher one.
+func prereleaseLine(latest semver, versions []semver) (semver, bool) {
+	line, found := semver{}, false
+	for _, v := range versions {
+		if v.pre != "" && latest.less(v) && (!found || line.less(v.release())) {
+			line, found = v.release(), true
+		}
+	}
+	return line, found
+}
+
+// bumpArgs contains arguments used for the bump command
+type bumpArgs struct {
+	pre       string
+	tag       bool
+	signed    bool
+	component string
+}
+
+// parseBumpArgs parses the parameters of the bump command.
+func parseBumpArgs(args []string) (bumpArgs, error) {
+	bArgs := bumpArgs{}
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "tag":
+			bArgs.tag = true
+		case "signed":
+			bArgs.signed = true
+		case "pre":
+			if i+1 >= len(args) {
+				return bArgs, fmt.Errorf("missing value for parameter: pre")
+			}
+			i++
+			bArgs.pre = args[i]
+		case "component":
+			if i+1 >= len(args) {
+				return bArgs, fmt.Errorf("missing value for parameter: component")
+			}
+			i++
+			bArgs.component = args[i]
+		default:
+			return bArgs, fmt.Errorf("unsupported parameter: %s", args[i])
+		}
+	}
+
+	return bArgs, nil
+}
+
+// versionTags returns every semantic version tagged on the history of rev with
+// the prefix, without the prefix. Without a prefix the tags of components, such as
+// ssh/v1.2.0, are left out as they are not semantic versions.
+func (c *CLI) versionTags(rev, prefix string) []semver {
+	out, err := c.ce.output("tag", "--list", "--merged", rev)
+	if err != nil || out == "" {
+		return nil
+	}
+
+	var versions []semver
+	for _, tag := range strings.Split(out, "\n") {
+		version, ok := strings.CutPrefix(tag, prefix)
+		if !ok {
+			continue
+		}
+		if v, ok := parseSemver(version); ok {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+// bump works out the next version from the commits since the latest release, writes
+// it, and optionally creates an annotated or signed tag for it. For a component only
+// its commits and its tags are used, and the version is tagged with its prefix.
+func (c *CLI) bump(bArgs bumpArgs) error {
+	comp, err := c.cfg.findComponent(bArgs.component)
+	if err != nil {
+		fmt.Fprintln(c.Out, err)
+		return err
+	}
+
+	prefix := comp.prefix()
+	versions := c.versionTags("HEAD", prefix)
+
+	latest := semver{prefix: "v"}
+	found := false
+	for _, v := range versions {
+		if v.pre == "" && (!found || latest.less(v)) {
+			latest = v
+			found = true
+		}
+	}
+
+	revRange := "HEAD"
+	if found {
+		revRange = prefix + latest.String() + "..HEAD"
+	}
+
+	commits, err := c.readComponentCommits(comp, revRange)
+	if err != nil {
+		fmt.Fprintf(c.Out, "Error reading git log for %s: %s\n", revRange, err)
+		return err
+	}
+
+	level := bumpLevel(commits)
+	if level == bumpNone {
+		switch {
+		case found:
+			fmt.Fprintf(c.Out, "No features, fixes or breaking changes since %s%s\n", prefix, latest)
+		case len(versions) == 0:
+			fmt.Fprintln(c.Out, "No features, fixes or breaking changes in the history, and no version is tagged yet")
+		default:
+			fmt.Fprintln(c.Out, "No features, fixes or breaking changes in the history")
+		}
+		return &CCError{"Nothing to release"}
+	}
+
+	version := nextVersion(latest, versions, level, bArgs.pre)
+	if line, ok := prereleaseLine(latest, versions); ok && version.release().less(line) {
+		version = nextVersion(line, versions, bumpNone, bArgs.pre)
+	}
+
+	next := prefix + version.String()
+	fmt.Fprintln(c.Out, next)
+
+	if !bArgs.tag {
+		return nil
+	}
+
+	return c.runGit(c.ce.tag(next, "chore(release): "+next, bArgs.signed), "creating tag "+next)
+}
@@ -0,0 +1,219 @@
+package cc
+
+import (
+	"testing"
+)
+
+func TestParseSemver(t *testing.T) {
+	testCases := []struct {
+		tag string
+		ok  bool
+	}{
+		{tag: "v1.4.0", ok: true},
+		{tag: "1.4.0", ok: true},
+		{tag: "v1.4.0-rc.1", ok: true},
+		{tag: "v1.4", ok: false},
+		{tag: "release-1", ok: false},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.tag, func(t *testing.T) {
+			v, ok := parseSemver(tC.tag)
+
+			if ok != tC.ok {
+				t.Fatalf("got %t want %t", ok, tC.ok)
+			}
+
+			if ok && v.String() != tC.tag {
+				t.Errorf("got %q want %q", v.String(), tC.tag)
+			}
+		})
+	}
+}
+
+func TestBumpLevel(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		commits []commit
+		want    int
+	}{
+		{
+			desc:    "docs only",
+			commits: []commit{{cc: &CC{Type: "docs"}}, {cc: &CC{Type: "chore"}}},
+			want:    bumpNone,
+		},
+		{
+			desc:    "fix",
+			commits: []commit{{cc: &CC{Type: "docs"}}, {cc: &CC{Type: "fix"}}},
+			want:    bumpPatch,
+		},
+		{
+			desc:    "feat",
+			commits: []commit{{cc: &CC{Type: "fix"}}, {cc: &CC{Type: "feat"}}, {cc: &CC{Type: "fix"}}},
+			want:    bumpMinor,
+		},
+		{
+			desc:    "breaking header",
+			commits: []commit{{cc: &CC{Type: "feat"}}, {cc: &CC{Type: "refactor", Breaking: true}}},
+			want:    bumpMajor,
+		},
+		{
+			desc:    "breaking footer",
+			commits: []commit{{cc: &CC{Type: "fix", Footers: []Trailer{{Token: "BREAKING CHANGE", Value: "removed flag"}}}}},
+			want:    bumpMajor,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got := bumpLevel(tC.commits)
+
+			if got != tC.want {
+				t.Errorf("got %d want %d", got, tC.want)
+			}
+		})
+	}
+}
+
+func TestNextVersion(t *testing.T) {
+	versions := []semver{}
+	for _, tag := range []string{"v0.9.0", "v1.3.1", "v1.4.0-rc.1", "v1.4.0-rc.2", "v1.4.0-beta.1"} {
+		v, _ := parseSemver(tag)
+		versions = append(versions, v)
+	}
+
+	testCases := []struct {
+		latest string
+		level  int
+		pre    string
+		want   string
+	}{
+		{latest: "v1.3.1", level: bumpPatch, want: "v1.3.2"},
+		{latest: "v1.3.1", level: bumpMinor, want: "v1.4.0"},
+		{latest: "v1.3.1", level: bumpMajor, want: "v2.0.0"},
+		{latest: "v0.9.0", level: bumpMajor, want: "v0.10.0"},
+		{latest: "v0.9.0", level: bumpMinor, want: "v0.10.0"},
+		{latest: "v0.9.0", level: bumpPatch, want: "v0.9.1"},
+		{latest: "v1.3.1", level: bumpMinor, pre: "rc", want: "v1.4.0-rc.3"},
+		{latest: "v1.3.1", level: bumpMinor, pre: "alpha", want: "v1.4.0-alpha.1"},
+		{latest: "v1.3.1", level: bumpMajor, pre: "rc", want: "v2.0.0-rc.1"},
+		{latest: "1.3.1", level: bumpPatch, want: "1.3.2"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.want, func(t *testing.T) {
+			latest, _ := parseSemver(tC.latest)
+
+			got := nextVersion(latest, versions, tC.level, tC.pre).String()
+
+			if got != tC.want {
+				t.Errorf("got %q want %q", got, tC.want)
+			}
+		})
+	}
+}
+
+func TestBump(t *testing.T) {
+	t.Run("Next version written", func(t *testing.T) {
+		buffer, cli, ce := mockGitCLI(map[string]string{
+			"tag --list --merged HEAD":               "v1.3.1\nv1.4.0-rc.1\nnot-a-version",
+			"log --format=%H%x1f%B%x1e v1.3.1..HEAD": "aaa\x1ffeat: add bump\n\x1e",
+		})
+
+		if err := cli.bump(bumpArgs{pre: "rc", tag: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		got := buffer.String()
+		want := "v1.4.0-rc.2\n"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+
+		if ce.command != "tag" {
+			t.Errorf("tag not created")
+		}
+	})
+
+	t.Run("Prerelease line continued or released", func(t *testing.T) {
+		testCases := []struct {
+			tags string
+			log  string
+			pre  string
+			want string
+		}{
+			{tags: "v1.0.0-rc.1", log: "feat: add bump", pre: "rc", want: "v1.0.0-rc.2"},
+			{tags: "v1.0.0-rc.1", log: "fix: handle prereleases", want: "v1.0.0"},
+			{tags: "v0.9.0\nv1.0.0-rc.1", log: "feat: add bump", want: "v1.0.0"},
+			{tags: "v1.3.1\nv1.4.0-rc.1", log: "feat!: drop bump", pre: "rc", want: "v2.0.0-rc.1"},
+		}
+		for _, tC := range testCases {
+			t.Run(tC.want, func(t *testing.T) {
+				buffer, cli, _ := mockGitCLI(map[string]string{
+					"tag --list --merged HEAD":               tC.tags,
+					"log --format=%H%x1f%B%x1e HEAD":         "aaa\x1f" + tC.log + "\n\x1e",
+					"log --format=%H%x1f%B%x1e v0.9.0..HEAD": "aaa\x1f" + tC.log + "\n\x1e",
+					"log --format=%H%x1f%B%x1e v1.3.1..HEAD": "aaa\x1f" + tC.log + "\n\x1e",
+				})
+
+				if err := cli.bump(bumpArgs{pre: tC.pre}); err != nil {
+					t.Fatal(err)
+				}
+				if got := buffer.String(); got != tC.want+"\n" {
+					t.Errorf("got %q want %q", got, tC.want)
+				}
+			})
+		}
+	})
+
+	t.Run("Nothing tagged and nothing to release", func(t *testing.T) {
+		buffer, cli, _ := mockGitCLI(map[string]string{
+			"tag --list --merged HEAD":       "",
+			"log --format=%H%x1f%B%x1e HEAD": "aaa\x1fdocs: explain bump\n\x1e",
+		})
+
+		if err := cli.bump(bumpArgs{}); err == nil {
+			t.Errorf("expected an error")
+		}
+
+		want := "No features, fixes or breaking changes in the history, and no version is tagged yet\n"
+		if got := buffer.String(); got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("Nothing to release", func(t *testing.T) {
+		buffer, cli, _ := mockGitCLI(map[string]string{
+			"tag --list --merged HEAD":               "v1.3.1",
+			"log --format=%H%x1f%B%x1e v1.3.1..HEAD": "aaa\x1fdocs: explain bump\n\x1e",
+		})
+
+		if err := cli.bump(bumpArgs{}); err == nil {
+			t.Errorf("expected an error")
+		}
+
+		got := buffer.String()
+		want := "No features, fixes or breaking changes since v1.3.1\n"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+	t.Run("Failing tag reported", func(t *testing.T) {
+		buffer, cli, ce := mockGitCLI(map[string]string{
+			"tag --list --merged HEAD":               "v1.3.1",
+			"log --format=%H%x1f%B%x1e v1.3.1..HEAD": "aaa\x1ffix: handle tags\n\x1e",
+		})
+		ce.failing = "v1.3.2"
+
+		err := cli.bump(bumpArgs{tag: true})
+		if exitStatus(err) != 128 {
+			t.Errorf("got %v want git's exit status", err)
+		}
+
+		got := buffer.String()
+		want := "v1.3.2\nfatal: tag 'v1.3.2' already exists\nError creating tag v1.3.2, git exited with status 128\n"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+}
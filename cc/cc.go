@@ -1,93 +1,284 @@
-// Package cc provides functionality for making conventional commits.
+// Package cc provides functionality for making conventional commits. The CC type
+// parses, builds and validates conventional commit messages, and the CLI prompts
+// for its fields to make commits with git.
 package cc
 
 import (
 	"bufio"
 	"fmt"
 	"io"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 )
 
+// footerFormat is written when a footer is entered in an unsupported format.
+const footerFormat = "A footer must be written as <token>: <value> or <token> #<value>, such as Closes #12 or Reviewed-by: Name <email>"
+
+// ccParams are the parameters of the command that makes a conventional commit.
+var ccParams = []string{"signed", "breaking", "yes", "edit", "pair", "solo", "type", "scope", "subject", "body", "footer"}
+
 // CLI defines the cli for this package.
 type CLI struct {
-	Out io.Writer
-	In  *bufio.Scanner
-	cc  *CC
-	ce  CmdExecutor
+	Out    io.Writer
+	In     *bufio.Scanner
+	stdin  io.Reader
+	cc     *CC
+	ce     CmdExecutor
+	cfg    *config
+	yes    bool
+	edit   bool
+	editor func(path string) error
+	// pick chooses the type and scope, it is nil when there is no terminal to show it on
+	pick   picker
+	params map[string]bool
+	// message is the commit message built from cc
+	message string
+	signed  bool
+	// pair prompts for co-authors, and solo forgets the ones chosen earlier
+	pair bool
+	solo bool
+	// paths are the only files committed when splitting the staged changes, nil
+	// commits everything staged
+	paths []string
+	// amending is the commit whose message is rewritten instead of making a new commit
+	amending *amendTarget
+	// reverting is set while committing reverts, whose messages are not typed and
+	// are not saved as drafts
+	reverting bool
+	// mu guards cc against the interrupt handler once keepDraftOnInterrupt is
+	// called. The prompts hold it while they change the CC and release it while
+	// they wait for input, which is when Ctrl-C is pressed.
+	mu sync.Mutex
+	// interrupted receives Ctrl-C until the commit is made
+	interrupted chan os.Signal
 }
 
 // NewCLI creates a CLI for creating conventional commits
 func NewCLI(out io.Writer, in io.Reader, ce CmdExecutor) *CLI {
-	return &CLI{
-		Out: out,
-		In:  bufio.NewScanner(in),
-		cc:  &CC{},
-		ce:  ce,
+	cli := &CLI{
+		Out:    out,
+		In:     bufio.NewScanner(in),
+		stdin:  in,
+		cc:     &CC{},
+		ce:     ce,
+		cfg:    defaultConfig(),
+		editor: runEditor,
+		params: map[string]bool{},
+	}
+
+	if isTerminal(in, out) {
+		cli.pick = cli.terminalPicker(in.(*os.File))
 	}
+	return cli
 }
 
 // writeTypesPrompt writes conventional commit type options
 func (c *CLI) writeTypesPrompt() {
-	prompt := getTypesPrompt()
-	fmt.Fprint(c.Out, prompt)
+	prompt := c.cfg.typesPrompt()
+	fmt.Fprint(c.Out, withCurrent(prompt, c.cc.Type))
+}
+
+// promptType prompts for the type with the picker when there is a terminal, or
+// with the numbered list of types otherwise.
+func (c *CLI) promptType() error {
+	if c.pick != nil {
+		return c.pickType()
+	}
+	c.writeTypesPrompt()
+	return c.readType()
 }
 
-// getTypesPrompt combines all conventional commit types options into one prompt
-func getTypesPrompt() string {
-	typesString := ""
-	for _, v := range types {
-		typesString += v
+// pickType sets the conventional commit type chosen in the picker. Leaving the
+// picker keeps the current type, and is an error when there is none.
+func (c *CLI) pickType() error {
+	options := make([]option, len(c.cfg.Types))
+	for i, t := range c.cfg.Types {
+		options[i] = option{t.Name, t.Description}
 	}
-	typesString += "\nEnter a number between 0 and 7: "
-	return typesString
+
+	title := "Choose a type"
+	if c.cc.Type != "" {
+		title += " [" + c.cc.Type + "]"
+	}
+
+	if cctype, ok := c.pick(title, options, c.cc.Type, false); ok {
+		c.cc.Type = cctype
+	}
+	if c.cc.Type == "" {
+		fmt.Fprintln(c.Out, "No type chosen")
+		return &CCError{"No type chosen"}
+	}
+
+	fmt.Fprintf(c.Out, "Type: \033[36;1m%s\033[0m\n", c.cc.Type)
+	return nil
 }
 
 // readType will try to set a conventional commit type from the number input from user.
-// Will retry after an invalid input for three times before exiting the program.
-func (c *CLI) readType() {
+// Will retry after an invalid input for three times before returning an error.
+// An empty input keeps the current type, if there is one.
+func (c *CLI) readType() error {
 
 	fails := 0
 	var cctype string
 	for {
 		input := c.readLine()
-		val, ok := CCTypeMap[input]
+		val, ok := c.cfg.typeByNumber(input)
+		if input == "" && c.cc.Type != "" {
+			val, ok = c.cc.Type, true
+		}
 
 		if ok {
 			cctype = val
 			break
 		} else {
 			if fails > 1 {
-				break
+				fmt.Fprintln(c.Out, "No type chosen")
+				return &CCError{"No type chosen"}
 			}
 			fails++
-			fmt.Fprint(c.Out, "Enter a valid number between 0 and 7: ")
+			fmt.Fprint(c.Out, "Enter a valid "+strings.TrimPrefix(c.cfg.numberPrompt(), "Enter a "))
 		}
 	}
 
-	c.cc.typ = cctype
+	c.cc.Type = cctype
+	return nil
 }
 
-// readScope takes input and sets a scope for the conventional commit
+// readScope takes input and sets a scope for the conventional commit. Scopes derived
+// from the staged files are offered as numbered suggestions that can be picked or
+// overridden by entering a different scope. An empty input keeps the current scope,
+// if there is one, and - removes it. When there is a terminal, the suggestions and
+// the scopes used before are offered in the picker instead.
 func (c *CLI) readScope() {
-	fmt.Fprint(c.Out, "Enter a scope: ")
+	suggestions := c.suggestScopes()
+	current := c.cc.Scope
+
+	if c.pick != nil {
+		if options := c.scopeOptions(suggestions); len(options) > 0 {
+			c.pickScope(options)
+			return
+		}
+	}
+
+	if len(c.cfg.AllowedScopes) > 0 {
+		c.readAllowedScope(suggestions)
+		return
+	}
+
+	if len(suggestions) == 0 {
+		fmt.Fprint(c.Out, withCurrent("Enter a scope: ", current))
+	} else {
+		for i, v := range suggestions {
+			fmt.Fprintf(c.Out, "%-4s\033[36;1m%s\033[0m\n", strconv.Itoa(i)+".", v)
+		}
+		prompt := fmt.Sprintf("\nEnter a scope or a number between 0 and %d: ", len(suggestions)-1)
+		fmt.Fprint(c.Out, withCurrent(prompt, current))
+	}
 
 	input := c.readLine()
-	if input != "" {
-		input = "(" + input + ")"
+	if n, err := strconv.Atoi(input); err == nil && n >= 0 && n < len(suggestions) {
+		input = suggestions[n]
+	}
+
+	switch input {
+	case "":
+		input = current
+	case "-":
+		input = ""
+	}
+
+	c.cc.Scope = input
+}
+
+// readAllowedScope sets the conventional commit scope to one of the allowed scopes,
+// offered as numbered choices with the scopes of the staged files first. An empty
+// input keeps the current scope, and - removes it. Will retry after an invalid
+// input for three times before leaving the commit without a scope.
+func (c *CLI) readAllowedScope(suggestions []string) {
+	var choices []string
+	for _, s := range append(suggestions, c.cfg.AllowedScopes...) {
+		if c.cfg.isScope(s) && !containsFold(choices, s) {
+			choices = append(choices, s)
+		}
+	}
+
+	for i, v := range choices {
+		fmt.Fprintf(c.Out, "%-4s\033[36;1m%s\033[0m\n", strconv.Itoa(i)+".", v)
+	}
+	prompt := fmt.Sprintf("\nEnter a scope or a number between 0 and %d: ", len(choices)-1)
+	fmt.Fprint(c.Out, withCurrent(prompt, c.cc.Scope))
+
+	fails := 0
+	for {
+		input := c.readLine()
+		if n, err := strconv.Atoi(input); err == nil && n >= 0 && n < len(choices) {
+			input = choices[n]
+		}
+
+		switch input {
+		case "":
+			input = c.cc.Scope
+		case "-":
+			input = ""
+		}
+
+		if c.cfg.isScope(input) {
+			c.cc.Scope = input
+			return
+		}
+
+		if fails > 1 {
+			fmt.Fprintln(c.Out, "No scope chosen")
+			c.cc.Scope = ""
+			return
+		}
+		fails++
+		fmt.Fprintf(c.Out, "Scope must be one of: %s\n", strings.Join(c.cfg.AllowedScopes, ", "))
+		fmt.Fprint(c.Out, prompt)
+	}
+}
+
+// pickScope sets the conventional commit scope chosen in the picker, or entered
+// as a new one unless the scopes are restricted. Leaving the picker keeps the
+// current scope, and - removes it.
+func (c *CLI) pickScope(options []option) {
+	allowNew := len(c.cfg.AllowedScopes) == 0
+	title := "Choose a scope, type to filter or to enter a new one, - for none"
+	if !allowNew {
+		title = "Choose a scope, type to filter, - for none"
+	}
+	if c.cc.Scope != "" {
+		title += " [" + c.cc.Scope + "]"
 	}
 
-	c.cc.scope = input
+	scope, ok := c.pick(title, options, c.cc.Scope, allowNew)
+	if ok {
+		if scope == "-" {
+			scope = ""
+		}
+		c.cc.Scope = scope
+	}
+
+	if c.cc.Scope != "" {
+		fmt.Fprintf(c.Out, "Scope: \033[36;1m%s\033[0m\n", c.cc.Scope)
+	}
 }
 
 // readSubject will try to set a conventional commit subject from the user input.
 // Will retry after an invalid input for three times before exiting the program.
+// An empty input keeps the current subject, if there is one.
 func (c *CLI) readSubject() {
-	fmt.Fprint(c.Out, "Enter a subject: ")
+	fmt.Fprint(c.Out, withCurrent("Enter a subject: ", c.cc.Subject))
 
 	fails := 0
 	var subject string
 	for {
 		input := c.readLine()
+		if input == "" {
+			input = c.cc.Subject
+		}
 
 		if input != "" {
 			subject = input
@@ -101,50 +292,137 @@ func (c *CLI) readSubject() {
 		}
 	}
 
-	c.cc.subject = subject
+	c.cc.Subject = subject
 }
 
-// readBodyAndFooter will try to set a body and footer for the conventional commit.
+// readBodyAndFooter will try to set a body and footers for the conventional commit.
 func (c *CLI) readBodyAndFooter() {
-	fmt.Fprint(c.Out, "Enter a body: ")
-	inputBody := c.readLine()
-	c.cc.body = inputBody
+	c.readBody()
+	c.readFooters()
+}
 
-	fmt.Fprint(c.Out, "Enter a footer: ")
-	inputFooter := c.readLine()
-	c.cc.footer = inputFooter
+// readBody takes input and sets a body for the conventional commit. An empty input
+// keeps the current body, if there is one, and - removes it.
+func (c *CLI) readBody() {
+	fmt.Fprint(c.Out, withCurrent("Enter a body: ", c.cc.Body))
+
+	switch input := c.readLine(); input {
+	case "":
+	case "-":
+		c.cc.Body = ""
+	default:
+		c.cc.Body = input
+	}
 }
 
-// readLine reads a line from the CLI's input
-func (c *CLI) readLine() string {
-	c.In.Scan()
-	return c.In.Text()
+// readFooters takes footers for the conventional commit one at a time, until an
+// empty input is entered. Footers are added to the current ones, which - removes.
+func (c *CLI) readFooters() {
+	current := make([]string, len(c.cc.Footers))
+	for i, t := range c.cc.Footers {
+		current[i] = t.String()
+	}
+
+	prompt := withCurrent("Enter a footer: ", strings.Join(current, ", "))
+	for {
+		fmt.Fprint(c.Out, prompt)
+		prompt = "Enter a footer: "
+
+		input := c.readLine()
+		if input == "" {
+			return
+		}
+		if input == "-" {
+			c.cc.Footers = nil
+			continue
+		}
+
+		t, ok := parseTrailer(input)
+		if !ok {
+			fmt.Fprintln(c.Out, footerFormat)
+			continue
+		}
+		c.cc.Footers = append(c.cc.Footers, t.gitTrailer())
+	}
 }
 
-// buildMessage uses all the CC fields to create a conventional commit message
-func (c *CLI) buildMessage() {
-	message := ""
-	message += c.cc.typ
-	message += c.cc.scope
+// readBreakingChange will try to set a BREAKING CHANGE footer describing the breaking
+// change, when the commit is marked as breaking without one.
+// Will retry after an empty input for three times before returning an error, as a
+// breaking change must be described.
+func (c *CLI) readBreakingChange() error {
+	if !c.cc.Breaking || c.cc.breakingChange() != "" {
+		return nil
+	}
+
+	fmt.Fprint(c.Out, "Enter a description of the breaking change: ")
+
+	fails := 0
+	var description string
+	for {
+		input := c.readLine()
 
-	if c.cc.breaking {
-		message += "!"
+		if input != "" {
+			description = input
+			break
+		} else {
+			if fails > 1 {
+				fmt.Fprintln(c.Out, "No description of the breaking change entered")
+				return &CCError{"No breaking change description"}
+			}
+			fails++
+			fmt.Fprint(c.Out, "Enter a description of the breaking change: ")
+		}
 	}
 
-	message += ": "
-	message += c.cc.subject
+	c.cc.Footers = append(c.cc.Footers, Trailer{Token: breakingToken, Value: description}.gitTrailer())
+	return nil
+}
 
-	if c.cc.body != "" {
-		message += "\n\n"
-		message += c.cc.body
+// readMissingFields prompts the user only for the fields of the conventional commit
+// that were not already passed as parameters, and for a description of the breaking
+// change when the commit is marked as breaking without one. With the edit parameter the body and
+// footer are written in the user's editor instead, falling back to the prompts when
+// the editor cannot be run. An error is returned when no type is chosen or the
+// breaking change is not described.
+func (c *CLI) readMissingFields() error {
+	if !c.params["type"] {
+		if err := c.promptType(); err != nil {
+			return err
+		}
+	}
+	if !c.params["scope"] {
+		c.readScope()
+	}
+	if !c.params["subject"] {
+		c.readSubject()
+	}
+	if c.edit && c.editBodyAndFooter() == nil {
+		return c.readBreakingChange()
+	}
+	if !c.params["body"] {
+		c.readBody()
+	}
+	if !c.params["footer"] {
+		c.readFooters()
 	}
+	return c.readBreakingChange()
+}
 
-	if c.cc.footer != "" {
-		message += "\n\n"
-		message += c.cc.footer
+// readLine reads a line from the CLI's input
+func (c *CLI) readLine() string {
+	if c.interrupted != nil {
+		c.mu.Unlock()
+		defer c.mu.Lock()
 	}
+	c.In.Scan()
+	return c.In.Text()
+}
 
-	c.cc.message = message
+// buildMessage uses all the CC fields to create a conventional commit message,
+// laid out with the configured template.
+func (c *CLI) buildMessage() {
+	c.message = c.render(*c.cc)
 }
 
 // writeConfirmationPrompt writes a message to the user asking them to confirm if they
@@ -152,36 +430,115 @@ func (c *CLI) buildMessage() {
 func (c *CLI) writeConfirmationPrompt() {
 	start := "\n\nPotential commit message:\n\n"
 	end := "\n\nCommit these changes with the message [y/N]: "
-	fmt.Fprint(c.Out, start+"\033[36;1m"+c.cc.message+"\033[0m"+end)
+	fmt.Fprint(c.Out, start+"\033[36;1m"+c.message+"\033[0m"+end)
 }
 
-// makeCommit firsts prompts the user to confirm if they want to make a commit with the message.
+// makeCommit first writes the style rules the message breaks, offering to fix them,
+// then prompts the user to confirm if they want to make a commit with the message.
 // If the user responds with either a "y" or "yes" it will build the  CmdExecutor *exec.Cmd
-// and run it to make a conventional commit with git
-func (c *CLI) makeCommit() {
-	c.writeConfirmationPrompt()
+// and run it to make a conventional commit with git, of only the paths when splitting,
+// or rewrite the message of the commit being amended. The prompt is skipped when the
+// yes parameter was passed.
+// When git fails, its error output and exit status are written and its error returned.
+// The conventional commit is saved as a draft when the commit is not made, and its
+// scope is remembered to be offered again when it is.
+func (c *CLI) makeCommit() error {
+	c.reviewStyle()
+
+	input := "y"
+	if !c.yes {
+		c.writeConfirmationPrompt()
+		input = strings.ToLower(c.readLine())
+	}
 
-	input := strings.ToLower(c.readLine())
+	if input != "y" && input != "yes" {
+		c.keepDraft()
+		return nil
+	}
 
-	if input == "y" || input == "yes" {
-		cmd := c.ce.build(c.cc.message, c.cc.signed)
-		cmd.Stdout = c.Out
-		cmd.Run()
+	if c.amending != nil {
+		return c.amendCommit()
 	}
 
+	cmd := c.ce.build(c.message, c.signed)
+	if c.paths != nil {
+		cmd = c.ce.commitPaths(c.message, c.signed, c.paths)
+	}
+	c.stopDraftOnInterrupt()
+	if err := c.runGit(cmd, "making commit"); err != nil {
+		c.keepDraft()
+		return err
+	}
+	c.rememberScope()
+	return nil
 }
 
 // parseParams loops through all the parameters passed to the command
-// and updates the state of the CC accordingly.
+// and updates the state of the CC accordingly. The type, scope, subject,
+// body and footer parameters take the following argument as their value, and
+// footer may be passed more than once.
 func (c *CLI) parseParams(args []string) {
 	for i := 0; i < len(args); i++ {
 		param := args[i]
 
 		switch param {
 		case "signed":
-			c.cc.signed = true
+			c.signed = true
 		case "breaking":
-			c.cc.breaking = true
+			c.cc.Breaking = true
+		case "yes":
+			c.yes = true
+		case "edit":
+			c.edit = true
+		case "pair":
+			c.pair = true
+		case "solo":
+			c.solo = true
+		case "type", "scope", "subject", "body", "footer":
+			if i+1 >= len(args) {
+				fmt.Fprintf(c.Out, "Missing value for parameter: %s\n", param)
+				continue
+			}
+			i++
+			c.setField(param, args[i])
+		}
+	}
+}
+
+// setField sets a field of the CC from a parameter value and records that it
+// no longer needs to be prompted for. An unknown type, or a scope that is not
+// allowed, is ignored so that the user is prompted for it instead.
+func (c *CLI) setField(param, value string) {
+	switch param {
+	case "type":
+		if !c.cfg.isType(value) {
+			fmt.Fprintf(c.Out, "Unsupported type: %s\n", value)
+			return
+		}
+		c.cc.Type = value
+	case "scope":
+		if !c.cfg.isScope(value) {
+			fmt.Fprintf(c.Out, "Unsupported scope: %s\n", value)
+			return
+		}
+		c.cc.Scope = value
+	case "subject":
+		if value == "" {
+			return
+		}
+		c.cc.Subject = value
+	case "body":
+		c.cc.Body = value
+	case "footer":
+		if value == "" {
+			break
+		}
+		t, ok := parseTrailer(value)
+		if !ok {
+			fmt.Fprintln(c.Out, footerFormat)
+			return
 		}
+		c.cc.Footers = append(c.cc.Footers, t.gitTrailer())
 	}
+	c.params[param] = true
 }
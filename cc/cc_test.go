@@ -2,8 +2,11 @@ package cc
 
 import (
 	"bytes"
+	"errors"
 	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -15,7 +18,15 @@ func TestTypes(t *testing.T) {
 		cli.writeTypesPrompt()
 
 		got := buffer.String()
-		want := getTypesPrompt()
+		want := "0.  \033[36;1mbuild\033[0m:      Changes that affect the build system or external dependencies\n" +
+			"1.  \033[36;1mci\033[0m:         Changes to our CI configuration files and scripts\n" +
+			"2.  \033[36;1mdocs\033[0m:       Documentation only changes\n" +
+			"3.  \033[36;1mfeat\033[0m:       A new feature\n" +
+			"4.  \033[36;1mfix\033[0m:        A bug fix\n" +
+			"5.  \033[36;1mrefactor\033[0m:   A code change that neither fixes a bug nor adds a feature\n" +
+			"6.  \033[36;1mtest\033[0m:       Adding missing tests or correcting existing tests\n" +
+			"7.  \033[36;1mchore\033[0m:      Ad-hoc task that doesn't match other types\n" +
+			"\nEnter a number between 0 and 7: "
 
 		if got != want {
 			t.Errorf("got %q want %q", got, want)
@@ -65,8 +76,8 @@ func TestTypes(t *testing.T) {
 
 			cli.readType()
 
-			if cli.cc.typ != tC.want {
-				t.Errorf("got %q want %q", cli.cc.typ, tC.want)
+			if cli.cc.Type != tC.want {
+				t.Errorf("got %q want %q", cli.cc.Type, tC.want)
 			}
 		}
 	})
@@ -74,15 +85,19 @@ func TestTypes(t *testing.T) {
 	t.Run("Type chosen incorrectly results in prompt", func(t *testing.T) {
 		buffer, cli, _ := mockCLI("")
 
-		cli.readType()
+		err := cli.readType()
 
 		got := buffer.String()
-		want := typeErrorMsg()
+		want := typeErrorMsg() + "No type chosen\n"
 
 		if got != want {
 			t.Errorf("got %q want %q", got, want)
 		}
 
+		if err == nil {
+			t.Errorf("got no error want no type chosen")
+		}
+
 	})
 }
 
@@ -99,8 +114,8 @@ func TestScope(t *testing.T) {
 			t.Errorf("got %q want %q", promptGot, promptWant)
 		}
 
-		got := cli.cc.scope
-		want := "(dependency)"
+		got := cli.cc.Scope
+		want := "dependency"
 
 		if got != want {
 			t.Errorf("got %q want %q", got, want)
@@ -118,7 +133,7 @@ func TestScope(t *testing.T) {
 			t.Errorf("got %q want %q", promptGot, promptWant)
 		}
 
-		got := cli.cc.scope
+		got := cli.cc.Scope
 		want := ""
 
 		if got != want {
@@ -142,7 +157,7 @@ func TestSubject(t *testing.T) {
 			t.Errorf("got %q want %q", promptGot, promptWant)
 		}
 
-		got := cli.cc.subject
+		got := cli.cc.Subject
 		want := subject
 
 		if got != want {
@@ -161,7 +176,7 @@ func TestSubject(t *testing.T) {
 			t.Errorf("got %q want %q", promptGot, promptWant)
 		}
 
-		got := cli.cc.subject
+		got := cli.cc.Subject
 		want := ""
 
 		if got != want {
@@ -175,26 +190,25 @@ func TestBodyAndFooter(t *testing.T) {
 	t.Run("Body and Footer Correctly Set", func(t *testing.T) {
 
 		dummyBody := "body"
-		dummyFooter := "footer"
-		buffer, cli, _ := mockCLI(dummyBody, dummyFooter)
+		buffer, cli, _ := mockCLI(dummyBody, "Closes #12", "free text", "reviewed-by: Z", "")
 
 		cli.readBodyAndFooter()
 
 		promptGot := buffer.String()
-		promptWant := "Enter a body: Enter a footer: "
+		promptWant := "Enter a body: Enter a footer: Enter a footer: " + footerFormat + "\nEnter a footer: Enter a footer: "
 		if promptGot != promptWant {
 			t.Errorf("got %q want %q", promptGot, promptWant)
 		}
 
-		gotBody := cli.cc.body
+		gotBody := cli.cc.Body
 		wantBody := dummyBody
 
 		if gotBody != wantBody {
 			t.Errorf("got %q want %q", gotBody, wantBody)
 		}
 
-		gotFooter := cli.cc.footer
-		wantFooter := dummyFooter
+		gotFooter := cli.cc.footer()
+		wantFooter := "Closes: #12\nReviewed-by: Z"
 
 		if gotFooter != wantFooter {
 			t.Errorf("got %q want %q", gotFooter, wantFooter)
@@ -209,7 +223,7 @@ func TestMessage(t *testing.T) {
 		scope := "dummy scope"
 		subject := "dummy subject"
 		body := "dummy body"
-		footer := "dummmy footer"
+		footer := "Closes #12"
 		_, cli, _ := mockCLI(typ, scope, subject, body, footer)
 
 		cli.readType()
@@ -218,8 +232,8 @@ func TestMessage(t *testing.T) {
 		cli.readBodyAndFooter()
 		cli.buildMessage()
 
-		got := cli.cc.message
-		want := CCTypeMap[typ] + "(" + scope + "): " + subject + "\n\n" + body + "\n\n" + footer
+		got := cli.message
+		want := defaultTypes[1].Name + "(" + scope + "): " + subject + "\n\n" + body + "\n\nCloses: #12"
 
 		if got != want {
 			t.Errorf("got %q want %q", got, want)
@@ -240,8 +254,8 @@ func TestMessage(t *testing.T) {
 		cli.readBodyAndFooter()
 		cli.buildMessage()
 
-		got := cli.cc.message
-		want := CCTypeMap[typ] + "(" + scope + "): " + subject
+		got := cli.message
+		want := defaultTypes[1].Name + "(" + scope + "): " + subject
 
 		if got != want {
 			t.Errorf("got %q want %q", got, want)
@@ -254,7 +268,7 @@ func TestConfirmationPrompt(t *testing.T) {
 	scope := "dummy scope"
 	subject := "dummy subject"
 	body := "dummy body"
-	footer := "dummmy footer"
+	footer := "Closes #12"
 	buffer, cli, _ := mockCLI(typ, scope, subject, body, footer)
 
 	cli.readType()
@@ -265,8 +279,8 @@ func TestConfirmationPrompt(t *testing.T) {
 	cli.writeConfirmationPrompt()
 
 	// slice to ignore the other prompts
-	got := buffer.String()[62:]
-	wantMsg := CCTypeMap[typ] + "(" + scope + "): " + subject + "\n\n" + body + "\n\n" + footer
+	got := buffer.String()[78:]
+	wantMsg := defaultTypes[1].Name + "(" + scope + "): " + subject + "\n\n" + body + "\n\nCloses: #12"
 	want := "\n\nPotential commit message:\n\n" + "\033[36;1m" + wantMsg + "\033[0m" + "\n\nCommit these changes with the message [y/N]: "
 
 	if got != want {
@@ -279,7 +293,7 @@ func TestCommandExecuted(t *testing.T) {
 	scope := "dummy scope"
 	subject := "dummy subject"
 	body := "dummy body"
-	footer := "dummmy footer"
+	footer := "Closes #12"
 
 	testCases := []struct {
 		confirm string
@@ -302,7 +316,7 @@ func TestCommandExecuted(t *testing.T) {
 	}
 	for _, tC := range testCases {
 		t.Run(tC.confirm+" confirmation", func(t *testing.T) {
-			_, cli, ce := mockCLI(typ, scope, subject, body, footer, tC.confirm)
+			_, cli, ce := mockCLI(typ, scope, subject, body, footer, "", tC.confirm)
 
 			cli.readType()
 			cli.readScope()
@@ -330,7 +344,7 @@ func TestParseParams(t *testing.T) {
 
 		cli.parseParams(args)
 
-		got := cli.cc.signed
+		got := cli.signed
 		want := false
 
 		if got != want {
@@ -344,7 +358,79 @@ func TestParseParams(t *testing.T) {
 
 		cli.parseParams(args)
 
-		got := cli.cc.signed
+		got := cli.signed
+		want := true
+
+		if got != want {
+			t.Errorf("got %t want %t", got, want)
+		}
+	})
+
+	t.Run("Fields set from key value params", func(t *testing.T) {
+		_, cli, _ := mockCLI()
+		args := []string{"type", "feat", "scope", "cc", "subject", "add params", "body", "", "footer", "Closes #1"}
+
+		cli.parseParams(args)
+
+		got := []string{cli.cc.Type, cli.cc.Scope, cli.cc.Subject, cli.cc.Body, cli.cc.footer()}
+		want := []string{"feat", "cc", "add params", "", "Closes: #1"}
+
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("got %q want %q", got[i], want[i])
+			}
+		}
+
+		for _, p := range []string{"type", "scope", "subject", "body", "footer"} {
+			if !cli.params[p] {
+				t.Errorf("param %q not recorded", p)
+			}
+		}
+	})
+
+	t.Run("Footer repeated", func(t *testing.T) {
+		buffer, cli, _ := mockCLI()
+		args := []string{"footer", "Closes #1", "footer", "not a trailer", "footer", "Co-authored-by: Z <z@example.com>"}
+
+		cli.parseParams(args)
+
+		got := cli.cc.footer()
+		want := "Closes: #1\nCo-authored-by: Z <z@example.com>"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+
+		if buffer.String() != footerFormat+"\n" {
+			t.Errorf("got %q want %q", buffer.String(), footerFormat+"\n")
+		}
+	})
+
+	t.Run("Unsupported type not set", func(t *testing.T) {
+		buffer, cli, _ := mockCLI()
+		args := []string{"type", "feature"}
+
+		cli.parseParams(args)
+
+		if cli.params["type"] {
+			t.Errorf("unsupported type recorded as param")
+		}
+
+		got := buffer.String()
+		want := "Unsupported type: feature\n"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("Yes set correctly", func(t *testing.T) {
+		_, cli, _ := mockCLI()
+		args := []string{"yes"}
+
+		cli.parseParams(args)
+
+		got := cli.yes
 		want := true
 
 		if got != want {
@@ -353,6 +439,101 @@ func TestParseParams(t *testing.T) {
 	})
 }
 
+func TestReadMissingFields(t *testing.T) {
+	t.Run("Only missing fields prompted", func(t *testing.T) {
+		buffer, cli, _ := mockCLI("dummy body")
+		args := []string{"type", "fix", "scope", "", "subject", "dummy subject", "footer", ""}
+
+		cli.parseParams(args)
+		cli.readMissingFields()
+		cli.buildMessage()
+
+		promptGot := buffer.String()
+		promptWant := "Enter a body: "
+		if promptGot != promptWant {
+			t.Errorf("got %q want %q", promptGot, promptWant)
+		}
+
+		got := cli.message
+		want := "fix: dummy subject\n\ndummy body"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("Commit made without confirmation", func(t *testing.T) {
+		buffer, cli, ce := mockCLI()
+		args := []string{"type", "fix", "scope", "", "subject", "dummy subject", "body", "", "footer", "", "yes"}
+
+		cli.parseParams(args)
+		cli.readMissingFields()
+		cli.buildMessage()
+		cli.makeCommit()
+
+		if buffer.String() != "" {
+			t.Errorf("got %q want no prompts", buffer.String())
+		}
+
+		got := ce.command
+		want := "execute"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+}
+
+func TestBreakingChange(t *testing.T) {
+	t.Run("Description required when breaking", func(t *testing.T) {
+		buffer, cli, _ := mockCLI("", "params are required")
+		args := []string{"type", "feat", "scope", "", "subject", "drop prompts", "body", "", "footer", "Refs #3", "breaking"}
+
+		cli.parseParams(args)
+		cli.readMissingFields()
+		cli.buildMessage()
+
+		promptGot := buffer.String()
+		promptWant := "Enter a description of the breaking change: Enter a description of the breaking change: "
+		if promptGot != promptWant {
+			t.Errorf("got %q want %q", promptGot, promptWant)
+		}
+
+		got := cli.message
+		want := "feat!: drop prompts\n\nRefs: #3\nBREAKING-CHANGE: params are required"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("Commit stopped without a description", func(t *testing.T) {
+		buffer, cli, _ := mockCLI("", "", "")
+		args := []string{"type", "feat", "scope", "", "subject", "drop prompts", "body", "", "footer", "", "breaking"}
+
+		cli.parseParams(args)
+		if err := cli.readMissingFields(); err == nil {
+			t.Fatal("got no error")
+		}
+
+		if got := buffer.String(); !strings.HasSuffix(got, "No description of the breaking change entered\n") {
+			t.Errorf("got %q want the commit stopped", got)
+		}
+	})
+
+	t.Run("Existing footer used as description", func(t *testing.T) {
+		buffer, cli, _ := mockCLI()
+		args := []string{"type", "feat", "subject", "drop prompts", "body", "", "footer", "BREAKING CHANGE: params are required", "breaking", "scope", ""}
+
+		cli.parseParams(args)
+		cli.readMissingFields()
+
+		if buffer.String() != "" {
+			t.Errorf("got %q want no prompts", buffer.String())
+		}
+	})
+}
+
 func userSends(messages ...string) io.Reader {
 	return strings.NewReader(strings.Join(messages, "\n"))
 }
@@ -373,11 +554,94 @@ func mockCLI(messages ...string) (*bytes.Buffer, *CLI, *mockCommandExecutor) {
 	return &buffer, cli, &ce
 }
 
+// mockGitCLI returns a mock CLI whose git commands write the outputs, keyed by
+// their arguments joined with spaces. The outputs are copied, so that a test can
+// change them without affecting the others.
+func mockGitCLI(outputs map[string]string, messages ...string) (*bytes.Buffer, *CLI, *mockCommandExecutor) {
+	buffer, cli, ce := mockCLI(messages...)
+	ce.outputs = map[string]string{}
+	for args, out := range outputs {
+		ce.outputs[args] = out
+	}
+	return buffer, cli, ce
+}
+
+// writeFiles writes the files into a temporary directory, creating the
+// directories they are in, and returns the directory.
+func writeFiles(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
 type mockCommandExecutor struct {
 	command string
+	outputs map[string]string
+	// commits are the paths of the commits made with commitPaths, and failing
+	// is the paths of the one that fails or the name of the tag that fails
+	commits []string
+	failing string
 }
 
 func (mce *mockCommandExecutor) build(message string, signed bool) *exec.Cmd {
 	mce.command = "execute"
-	return exec.Command(mce.command)
+	return exec.Command("true")
+}
+
+func (mce *mockCommandExecutor) commitPaths(message string, signed bool, paths []string) *exec.Cmd {
+	mce.command = "commit"
+	if strings.Join(paths, " ") == mce.failing {
+		return exec.Command("false")
+	}
+	mce.commits = append(mce.commits, strings.Join(paths, " "))
+	return exec.Command("true")
+}
+
+func (mce *mockCommandExecutor) amend(message string, signed bool) *exec.Cmd {
+	mce.command = "amend"
+	return exec.Command("true")
+}
+
+func (mce *mockCommandExecutor) fixup(message string, signed bool) *exec.Cmd {
+	mce.command = "fixup"
+	return exec.Command("true")
+}
+
+func (mce *mockCommandExecutor) autosquash(base string, signed bool) *exec.Cmd {
+	mce.command = "autosquash"
+	return exec.Command("true")
+}
+
+func (mce *mockCommandExecutor) revert(sha string) *exec.Cmd {
+	mce.command = "revert " + sha
+	return exec.Command("true")
+}
+
+func (mce *mockCommandExecutor) add(args ...string) *exec.Cmd {
+	mce.command = "add " + strings.Join(args, " ")
+	return exec.Command("true")
+}
+
+func (mce *mockCommandExecutor) tag(name, message string, signed bool) *exec.Cmd {
+	mce.command = "tag"
+	if name == mce.failing {
+		return exec.Command("sh", "-c", `echo "fatal: tag '$0' already exists" >&2; exit 128`, name)
+	}
+	return exec.Command("true")
+}
+
+func (mce *mockCommandExecutor) output(args ...string) (string, error) {
+	out, ok := mce.outputs[strings.Join(args, " ")]
+	if !ok {
+		return "", errors.New("git " + strings.Join(args, " ") + " failed")
+	}
+	return out, nil
 }
@@ -1,52 +1,141 @@
 package cc
 
 import (
+	"fmt"
+	"os"
 	"os/exec"
+	"strings"
 )
 
-// CC represents a conventional commit
+// CC represents a conventional commit, written as:
+//
+//	<type>(<scope>)!: <subject>
+//
+//	<body>
+//
+//	<footers>
 type CC struct {
-	typ      string
-	scope    string
-	subject  string
-	body     string
-	footer   string
-	message  string
-	signed   bool
-	breaking bool
+	Type     string    `json:"type"`
+	Scope    string    `json:"scope,omitempty"`
+	Breaking bool      `json:"breaking,omitempty"`
+	Subject  string    `json:"subject"`
+	Body     string    `json:"body,omitempty"`
+	Footers  []Trailer `json:"footers,omitempty"`
 }
 
-// An array of strings corrosponding to a type used in a conventional commit.
-//
-// Each string is in the format of: `<number>. <type> <description>`. With the type being
-// wrapped in cyan foreground color escape codes.
-var types = [11]string{
-	"0.  \033[36;1mbuild\033[0m:      Changes that affect the build system or external dependencies\n",
-	"1.  \033[36;1mci\033[0m:         Changes to our CI configuration files and scripts\n",
-	"2.  \033[36;1mdocs\033[0m:       Documentation only changes\n",
-	"3.  \033[36;1mfeat\033[0m:       A new feature\n",
-	"4.  \033[36;1mfix\033[0m:        A bug fix\n",
-	"5.  \033[36;1mrefactor\033[0m:   A code change that neither fixes a bug nor adds a feature\n",
-	"6.  \033[36;1mtest\033[0m:       Adding missing tests or correcting existing tests\n",
-	"7.  \033[36;1mchore\033[0m:      Ad-hoc task that doesn't match other types\n",
-}
-
-// CCTypeMap associates a number with each name of a conventional commit type.
-// Meant to be used by the CLI to get a type name given a number input.
-var CCTypeMap = map[string]string{
-	"0": "build",
-	"1": "ci",
-	"2": "docs",
-	"3": "feat",
-	"4": "fix",
-	"5": "refactor",
-	"6": "test",
-	"7": "chore",
-}
-
-// CmdExecutor defines behavior of building an exec.Command
+// Parse parses a commit message into a CC. Comments and everything after the
+// scissors line are removed first, the same way git cleans up a message. When the
+// message does not follow the conventional commit format, the fields that could be
+// parsed are returned along with a *FormatError.
+func Parse(message string) (CC, error) {
+	cc, errs := parseMessage(message)
+	if len(errs) > 0 {
+		return *cc, &FormatError{Errors: errs}
+	}
+	return *cc, nil
+}
+
+// String returns the commit message for the CC. Parse and String round-trip
+// exactly for messages that git has cleaned up, which have no comments and no
+// trailing newline.
+func (cc CC) String() string {
+	message := cc.Type
+	if cc.Scope != "" {
+		message += "(" + cc.Scope + ")"
+	}
+
+	if cc.Breaking {
+		message += "!"
+	}
+
+	message += ": " + cc.Subject
+
+	if cc.Body != "" {
+		message += "\n\n" + cc.Body
+	}
+
+	if len(cc.Footers) > 0 {
+		message += "\n\n" + cc.footer()
+	}
+	return message
+}
+
+// Validate reports whether the CC makes a commit message that follows the
+// conventional commit format, returning a *FormatError when it does not.
+func (cc CC) Validate() error {
+	_, err := Parse(cc.String())
+	return err
+}
+
+// FormatError is the error returned by Parse and Validate, listing every way in
+// which a commit message breaks the conventional commit format.
+type FormatError struct {
+	Errors []*LintError
+}
+
+// Error returns the first error along with the number of other errors.
+func (e *FormatError) Error() string {
+	switch len(e.Errors) {
+	case 0:
+		return "commit message does not follow the conventional commit format"
+	case 1:
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more)", e.Errors[0], len(e.Errors)-1)
+}
+
+// Unwrap returns the errors so that errors.As finds the *LintError of each of them.
+func (e *FormatError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// CCError is a custom error type for errors making conventional commits.
+type CCError struct {
+	Message string
+}
+
+// Error returns the error message for the custom error type.
+func (e *CCError) Error() string {
+	return e.Message
+}
+
+// ccType is a conventional commit type along with a description of when it should be used
+// and the title of the changelog section its commits are listed under.
+type ccType struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Section     string `yaml:"section"`
+}
+
+// defaultTypes are the conventional commit types offered when none are configured, in the
+// order they are numbered in the prompt.
+var defaultTypes = []ccType{
+	{Name: "build", Description: "Changes that affect the build system or external dependencies"},
+	{Name: "ci", Description: "Changes to our CI configuration files and scripts"},
+	{Name: "docs", Description: "Documentation only changes"},
+	{Name: "feat", Description: "A new feature"},
+	{Name: "fix", Description: "A bug fix"},
+	{Name: "refactor", Description: "A code change that neither fixes a bug nor adds a feature"},
+	{Name: "test", Description: "Adding missing tests or correcting existing tests"},
+	{Name: "chore", Description: "Ad-hoc task that doesn't match other types"},
+}
+
+// CmdExecutor defines behavior of building an exec.Command and of reading
+// the output of git commands
 type CmdExecutor interface {
 	build(message string, signed bool) *exec.Cmd
+	commitPaths(message string, signed bool, paths []string) *exec.Cmd
+	amend(message string, signed bool) *exec.Cmd
+	fixup(message string, signed bool) *exec.Cmd
+	autosquash(base string, signed bool) *exec.Cmd
+	revert(sha string) *exec.Cmd
+	add(args ...string) *exec.Cmd
+	tag(name, message string, signed bool) *exec.Cmd
+	output(args ...string) (string, error)
 }
 
 // CCExecutor implements CmdExecutor
@@ -62,3 +151,82 @@ func (ce *CCExecutor) build(message string, signed bool) *exec.Cmd {
 	}
 	return execCmd
 }
+
+// commitPaths creates and returns an *exec.Cmd for making a git commit of only the
+// given paths, leaving the other staged changes staged
+func (ce *CCExecutor) commitPaths(message string, signed bool, paths []string) *exec.Cmd {
+	args := []string{"commit", "-m", message}
+	if signed {
+		args = []string{"commit", "-S", "-m", message}
+	}
+	return exec.Command("git", append(append(args, "--"), paths...)...)
+}
+
+// amend creates and returns an *exec.Cmd for replacing the message of the last
+// commit, leaving out any staged changes
+func (ce *CCExecutor) amend(message string, signed bool) *exec.Cmd {
+	execCmd := exec.Command("git", "commit", "--amend", "--only", "-m", message)
+
+	if signed {
+		execCmd = exec.Command("git", "commit", "--amend", "--only", "-S", "-m", message)
+	}
+	return execCmd
+}
+
+// fixup creates and returns an *exec.Cmd for making an empty commit, such as an
+// amend! commit that rewords an older commit, leaving out any staged changes
+func (ce *CCExecutor) fixup(message string, signed bool) *exec.Cmd {
+	execCmd := exec.Command("git", "commit", "--allow-empty", "--only", "-m", message)
+
+	if signed {
+		execCmd = exec.Command("git", "commit", "--allow-empty", "--only", "-S", "-m", message)
+	}
+	return execCmd
+}
+
+// autosquash creates and returns an *exec.Cmd for an interactive rebase onto base
+// that squashes fixup! and amend! commits without opening an editor. An empty base
+// rebases from the root commit.
+func (ce *CCExecutor) autosquash(base string, signed bool) *exec.Cmd {
+	args := []string{"rebase", "--interactive", "--autosquash", "--autostash", "--rebase-merges"}
+	if signed {
+		args = append(args, "-S")
+	}
+
+	if base == "" {
+		args = append(args, "--root")
+	} else {
+		args = append(args, base)
+	}
+
+	execCmd := exec.Command("git", args...)
+	execCmd.Env = append(os.Environ(), "GIT_SEQUENCE_EDITOR=:")
+	return execCmd
+}
+
+// revert creates and returns an *exec.Cmd for applying the reverse of a commit to
+// the work tree and the index without committing it
+func (ce *CCExecutor) revert(sha string) *exec.Cmd {
+	return exec.Command("git", "revert", "--no-commit", sha)
+}
+
+// add creates and returns an *exec.Cmd for staging changes with git add
+func (ce *CCExecutor) add(args ...string) *exec.Cmd {
+	return exec.Command("git", append([]string{"add"}, args...)...)
+}
+
+// tag creates and returns an *exec.Cmd for making annotated git tags
+func (ce *CCExecutor) tag(name, message string, signed bool) *exec.Cmd {
+	execCmd := exec.Command("git", "tag", "-a", name, "-m", message)
+
+	if signed {
+		execCmd = exec.Command("git", "tag", "-s", name, "-m", message)
+	}
+	return execCmd
+}
+
+// output runs git with the given arguments and returns its trimmed standard output
+func (ce *CCExecutor) output(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	return strings.TrimSpace(string(out)), err
+}
@@ -0,0 +1,91 @@
+package cc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	messages := []string{
+		"feat: add parser",
+		"fix(lint)!: handle scissors",
+		"refactor( cc ): keep spaces in scope",
+		"docs: add api\n\nFirst paragraph\nwrapped.\n\n\nSecond paragraph after two blank lines.",
+		"feat(cc): add footers\n\nBody.\n\nCloses #12\nRefs: #7\nBREAKING CHANGE: footers are trailers\n  that span lines\nCo-authored-by: Z <z@example.com>",
+		"chore: only footers\n\nSigned-off-by: Z <z@example.com>",
+	}
+	for _, message := range messages {
+		t.Run(message, func(t *testing.T) {
+			cc, err := Parse(message)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := cc.String(); got != message {
+				t.Errorf("got %q want %q", got, message)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Run("Fields parsed", func(t *testing.T) {
+		cc, err := Parse("feat(cc)!: export api\n\nBody.\n\nRefs #3")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := CC{Type: "feat", Scope: "cc", Breaking: true, Subject: "export api", Body: "Body.", Footers: []Trailer{{"Refs", " ", "#3"}}}
+		if cc.String() != want.String() || cc.Scope != want.Scope || cc.Footers[0] != want.Footers[0] {
+			t.Errorf("got %+v want %+v", cc, want)
+		}
+	})
+
+	t.Run("Typed error describes what failed", func(t *testing.T) {
+		cc, err := Parse("feat(cc)!export: api\nbody")
+
+		var formatErr *FormatError
+		if !errors.As(err, &formatErr) {
+			t.Fatalf("got %v want a *FormatError", err)
+		}
+
+		var lintErr *LintError
+		if !errors.As(err, &lintErr) || lintErr.Rule != "header-format" {
+			t.Errorf("got %v want a header-format *LintError", lintErr)
+		}
+
+		got := err.Error()
+		want := `1:10: expected ":" after the type and scope [header-format] (and 1 more)`
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+
+		if cc.Type != "feat" || cc.Scope != "cc" {
+			t.Errorf("got %+v want the fields that could be parsed", cc)
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		desc string
+		cc   CC
+		rule string
+	}{
+		{desc: "Valid", cc: CC{Type: "feat", Subject: "add api"}},
+		{desc: "Missing type", cc: CC{Subject: "add api"}, rule: "type-empty"},
+		{desc: "Missing subject", cc: CC{Type: "feat", Scope: "cc"}, rule: "subject-empty"},
+		{desc: "Subject over several lines", cc: CC{Type: "feat", Subject: "add\napi"}, rule: "body-leading-blank"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := tC.cc.Validate()
+
+			var lintErr *LintError
+			errors.As(err, &lintErr)
+			if (tC.rule == "" && err != nil) || (tC.rule != "" && (lintErr == nil || lintErr.Rule != tC.rule)) {
+				t.Errorf("got %v want rule %q", err, tC.rule)
+			}
+		})
+	}
+}
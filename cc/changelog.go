@@ -0,0 +1,377 @@
+package cc
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Separators used in git log formats to split the output into commits and fields.
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+// changelogFile is the file a changelog is written to.
+const changelogFile = "CHANGELOG.md"
+
+// knownSections are well known conventional commit types along with the title of
+// the changelog section their commits are listed under.
+var knownSections = []ccType{
+	{Name: "feat", Section: "Features"},
+	{Name: "fix", Section: "Bug Fixes"},
+	{Name: "perf", Section: "Performance Improvements"},
+	{Name: "revert", Section: "Reverts"},
+	{Name: "docs", Section: "Documentation"},
+	{Name: "style", Section: "Styles"},
+	{Name: "refactor", Section: "Code Refactoring"},
+	{Name: "test", Section: "Tests"},
+	{Name: "build", Section: "Build System"},
+	{Name: "ci", Section: "Continuous Integration"},
+	{Name: "chore", Section: "Miscellaneous Chores"},
+}
+
+// commit is a commit from the git history parsed as a conventional commit.
+type commit struct {
+	sha string
+	cc  *CC
+}
+
+// isBreaking reports whether the commit is marked as a breaking change in its
+// header or with a BREAKING CHANGE footer.
+func (cm commit) isBreaking() bool {
+	return cm.cc.Breaking || cm.cc.breakingChange() != ""
+}
+
+// changelogArgs contains arguments used for the changelog command
+type changelogArgs struct {
+	from      string
+	to        string
+	version   string
+	write     bool
+	component string
+}
+
+// parseChangelogArgs parses the parameters of the changelog command.
+func parseChangelogArgs(args []string) (changelogArgs, error) {
+	cArgs := changelogArgs{to: "HEAD"}
+
+	for i := 0; i < len(args); i++ {
+		param := args[i]
+
+		if param == "write" {
+			cArgs.write = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			return cArgs, fmt.Errorf("missing value for parameter: %s", param)
+		}
+		i++
+
+		switch param {
+		case "from":
+			cArgs.from = args[i]
+		case "to":
+			cArgs.to = args[i]
+		case "version":
+			cArgs.version = args[i]
+		case "component":
+			cArgs.component = args[i]
+		default:
+			return cArgs, fmt.Errorf("unsupported parameter: %s", param)
+		}
+	}
+
+	return cArgs, nil
+}
+
+// readCommits returns the commits in the revision range, newest first. Commits
+// that are not conventional commits are skipped.
+func (c *CLI) readCommits(revs ...string) ([]commit, error) {
+	args := append([]string{"log", "--format=%H%x1f%B%x1e"}, revs...)
+	out, err := c.ce.output(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []commit
+	for _, record := range strings.Split(out, recordSep) {
+		sha, message, ok := strings.Cut(strings.TrimSpace(record), fieldSep)
+		if !ok {
+			continue
+		}
+
+		cc, err := Parse(c.cfg.untemplate(message))
+		if err != nil {
+			continue
+		}
+		commits = append(commits, commit{sha: sha, cc: &cc})
+	}
+
+	return commits, nil
+}
+
+// latestTag returns the tag of the highest semantic version with the prefix that
+// is reachable from rev, prereleases included, or an empty string when there are
+// none. The tags are chosen the same way as by bump.
+func (c *CLI) latestTag(rev, prefix string) string {
+	latest, found := semver{}, false
+	for _, v := range c.versionTags(rev, prefix) {
+		if !found || latest.less(v) {
+			latest, found = v, true
+		}
+	}
+
+	if !found {
+		return ""
+	}
+	return prefix + latest.String()
+}
+
+// isTag reports whether rev is the name of a tag.
+func (c *CLI) isTag(rev string) bool {
+	out, err := c.ce.output("tag", "--list", rev)
+	return err == nil && out == rev
+}
+
+// repoURL returns the web address of the origin remote, or an empty string when
+// it cannot be determined.
+func (c *CLI) repoURL() string {
+	remote, err := c.ce.output("remote", "get-url", "origin")
+	if err != nil {
+		return ""
+	}
+	return webURL(remote)
+}
+
+// webURL converts a git remote address such as git@github.com:yemaney/z.git
+// into the https address of the repository.
+func webURL(remote string) string {
+	url := strings.TrimSuffix(remote, ".git")
+
+	switch {
+	case strings.HasPrefix(url, "https://"), strings.HasPrefix(url, "http://"):
+		return url
+	case strings.HasPrefix(url, "ssh://"):
+		url = strings.TrimPrefix(url, "ssh://")
+		if _, host, ok := strings.Cut(url, "@"); ok {
+			url = host
+		}
+		return "https://" + url
+	}
+
+	if _, host, ok := strings.Cut(url, "@"); ok {
+		return "https://" + strings.Replace(host, ":", "/", 1)
+	}
+	return ""
+}
+
+// release is a section of the changelog for a single version.
+type release struct {
+	version string
+	date    string
+	repo    string
+	from    string
+	to      string
+	commits []commit
+}
+
+// sectionFor returns the title of the changelog section for a type. Types that
+// are not configured with a section use the title of the well known type of
+// the same name, and are otherwise left out of the changelog.
+func (cfg *config) sectionFor(typ string) string {
+	for _, t := range append(cfg.Types, knownSections...) {
+		if t.Name == typ && t.Section != "" {
+			return t.Section
+		}
+	}
+	return ""
+}
+
+// sectionOrder returns the titles of the changelog sections in the order they
+// are written, features and bug fixes first and then in the order of the types.
+func (cfg *config) sectionOrder() []string {
+	var order []string
+	seen := map[string]bool{}
+
+	names := append([]string{"feat", "fix"}, cfg.typeNames()...)
+	for _, t := range knownSections {
+		names = append(names, t.Name)
+	}
+
+	for _, name := range names {
+		section := cfg.sectionFor(name)
+		if section == "" || seen[section] {
+			continue
+		}
+		seen[section] = true
+		order = append(order, section)
+	}
+	return order
+}
+
+// renderEntry renders a commit as a changelog list item linked to the commit.
+func (r release) renderEntry(cm commit, text string) string {
+	entry := "* "
+	if scope := cm.cc.Scope; scope != "" {
+		entry += "**" + scope + ":** "
+	}
+	entry += text
+
+	short := shortSHA(cm.sha)
+
+	if r.repo == "" {
+		return entry + " (" + short + ")"
+	}
+	return entry + " ([" + short + "](" + r.repo + "/commit/" + cm.sha + "))"
+}
+
+// renderRelease renders the changelog section for a release in the same layout
+// as the existing CHANGELOG.md, with breaking changes listed first.
+func (cfg *config) renderRelease(r release) string {
+	var b strings.Builder
+
+	version := strings.TrimPrefix(r.version, "v")
+	if r.repo != "" && r.from != "" {
+		b.WriteString(fmt.Sprintf("## [%s](%s/compare/%s...%s)", version, r.repo, r.from, r.to))
+	} else {
+		b.WriteString("## " + version)
+	}
+	if r.date != "" {
+		b.WriteString(" (" + r.date + ")")
+	}
+	b.WriteString("\n")
+
+	sections := map[string][]string{}
+	var breaking []string
+	for _, cm := range r.commits {
+		if cm.isBreaking() {
+			note := cm.cc.breakingChange()
+			if note == "" {
+				note = cm.cc.Subject
+			}
+			breaking = append(breaking, r.renderEntry(cm, note))
+		}
+
+		if section := cfg.sectionFor(cm.cc.Type); section != "" {
+			sections[section] = append(sections[section], r.renderEntry(cm, cm.cc.Subject))
+		}
+	}
+
+	if len(breaking) > 0 {
+		b.WriteString("\n\n### ⚠ BREAKING CHANGES\n\n" + strings.Join(breaking, "\n") + "\n")
+	}
+
+	for _, title := range cfg.sectionOrder() {
+		if entries := sections[title]; len(entries) > 0 {
+			b.WriteString("\n\n### " + title + "\n\n" + strings.Join(entries, "\n") + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// prependRelease inserts a rendered release above the newest release of an
+// existing changelog, leaving older releases untouched.
+func prependRelease(changelog, section string) string {
+	if changelog == "" {
+		return "# Changelog\n\n" + section
+	}
+
+	if strings.HasPrefix(changelog, "## ") {
+		return section + "\n" + changelog
+	}
+
+	if i := strings.Index(changelog, "\n## "); i >= 0 {
+		return changelog[:i+1] + section + "\n" + changelog[i+1:]
+	}
+
+	return strings.TrimRight(changelog, "\n") + "\n\n" + section
+}
+
+// hasRelease reports whether a changelog already has a section for version.
+func hasRelease(changelog, version string) bool {
+	version = strings.TrimPrefix(version, "v")
+	for _, line := range strings.Split(changelog, "\n") {
+		if strings.HasPrefix(line, "## ["+version+"]") || strings.HasPrefix(line, "## "+version+" ") || line == "## "+version {
+			return true
+		}
+	}
+	return false
+}
+
+// changelog renders the release for the commits between two revisions, and either
+// writes it or prepends it to the CHANGELOG.md in the current directory. For a
+// component only its commits and its tags are used, and the release is prepended
+// to the CHANGELOG.md at its path.
+func (c *CLI) changelog(cArgs changelogArgs) error {
+	comp, err := c.cfg.findComponent(cArgs.component)
+	if err != nil {
+		fmt.Fprintln(c.Out, err)
+		return err
+	}
+
+	// The previous release is looked for from the parent of to, as to is itself
+	// the latest tag when it is tagged.
+	if cArgs.from == "" {
+		cArgs.from = c.latestTag(cArgs.to+"^", comp.prefix())
+	}
+
+	revRange := cArgs.to
+	if cArgs.from != "" {
+		revRange = cArgs.from + ".." + cArgs.to
+	}
+
+	commits, err := c.readComponentCommits(comp, revRange)
+	if err != nil {
+		fmt.Fprintf(c.Out, "Error reading git log for %s: %s\n", revRange, err)
+		return err
+	}
+
+	r := release{
+		version: cArgs.version,
+		repo:    c.repoURL(),
+		from:    cArgs.from,
+		to:      cArgs.to,
+		commits: commits,
+	}
+
+	if r.version == "" {
+		r.version = "Unreleased"
+		if c.isTag(cArgs.to) {
+			r.version = comp.version(cArgs.to)
+		}
+	} else if !c.isTag(cArgs.to) {
+		r.to = comp.prefix() + cArgs.version
+	}
+
+	r.date, _ = c.ce.output("log", "-1", "--format=%cs", cArgs.to)
+
+	section := c.cfg.renderRelease(r)
+
+	if !cArgs.write {
+		fmt.Fprint(c.Out, section)
+		return nil
+	}
+
+	path := c.changelogPath(comp)
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(c.Out, "Error reading %s: %s\n", path, err)
+		return err
+	}
+
+	if hasRelease(string(existing), r.version) {
+		fmt.Fprintf(c.Out, "%s already has a section for %s\n", path, r.version)
+		return &CCError{"Release already in changelog"}
+	}
+
+	if err := os.WriteFile(path, []byte(prependRelease(string(existing), section)), 0644); err != nil {
+		fmt.Fprintf(c.Out, "Error writing %s: %s\n", path, err)
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "Added %s to %s\n", r.version, path)
+	return nil
+}
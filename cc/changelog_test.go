@@ -0,0 +1,182 @@
+package cc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWebURL(t *testing.T) {
+	testCases := []struct {
+		remote string
+		want   string
+	}{
+		{
+			remote: "git@github.com:yemaney/z.git",
+			want:   "https://github.com/yemaney/z",
+		},
+		{
+			remote: "https://github.com/yemaney/z.git",
+			want:   "https://github.com/yemaney/z",
+		},
+		{
+			remote: "ssh://git@gitlab.com/group/project.git",
+			want:   "https://gitlab.com/group/project",
+		},
+		{
+			remote: "/srv/git/z.git",
+			want:   "",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.remote, func(t *testing.T) {
+			got := webURL(tC.remote)
+
+			if got != tC.want {
+				t.Errorf("got %q want %q", got, tC.want)
+			}
+		})
+	}
+}
+
+func TestReadCommits(t *testing.T) {
+	_, cli, _ := mockGitCLI(map[string]string{
+		"log --format=%H%x1f%B%x1e v1.3.1..v1.4.0": "aaa\x1ffeat(aws): add create\n\x1e\nbbb\x1fnot conventional\n\x1e\nccc\x1ffix: add missing comma\n\nCloses #3\n",
+	})
+
+	commits, err := cli.readCommits("v1.3.1..v1.4.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(commits) != 2 {
+		t.Fatalf("got %d commits want 2", len(commits))
+	}
+
+	got := []string{commits[0].sha, commits[0].cc.Subject, commits[1].sha, commits[1].cc.footer()}
+	want := []string{"aaa", "add create", "ccc", "Closes #3"}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %q want %q", got[i], want[i])
+		}
+	}
+}
+
+func TestRenderRelease(t *testing.T) {
+	repo := "https://github.com/yemaney/z"
+	r := release{
+		version: "v1.4.0",
+		date:    "2024-03-31",
+		repo:    repo,
+		from:    "v1.3.1",
+		to:      "v1.4.0",
+		commits: []commit{
+			{sha: "e8bd9d038ff39405793b844378d4c0972fade86a", cc: &CC{Type: "feat", Scope: "aws", Subject: "add aws feat to list, get, start, stop ec2 instances"}},
+			{sha: "285b0e56df79e91bb44aecb04322e63c8021823f", cc: &CC{Type: "fix", Subject: "add missing comma in commands slice"}},
+			{sha: "b1033bb03cc5871522989e58aa7022c5de1875ee", cc: &CC{Type: "feat", Scope: "aws", Subject: "add cmds branches to create and delete ec2 instanes"}},
+			{sha: "1111111111111111111111111111111111111111", cc: &CC{Type: "refactor", Scope: "cc", Subject: "drop params", Footers: []Trailer{{Token: "BREAKING CHANGE", Value: "params are now required"}}}},
+			{sha: "2222222222222222222222222222222222222222", cc: &CC{Type: "unknown", Subject: "left out"}},
+		},
+	}
+
+	got := defaultConfig().renderRelease(r)
+	want := `## [1.4.0](https://github.com/yemaney/z/compare/v1.3.1...v1.4.0) (2024-03-31)
+
+
+### ⚠ BREAKING CHANGES
+
+* **cc:** params are now required ([1111111](https://github.com/yemaney/z/commit/1111111111111111111111111111111111111111))
+
+
+### Features
+
+* **aws:** add aws feat to list, get, start, stop ec2 instances ([e8bd9d0](https://github.com/yemaney/z/commit/e8bd9d038ff39405793b844378d4c0972fade86a))
+* **aws:** add cmds branches to create and delete ec2 instanes ([b1033bb](https://github.com/yemaney/z/commit/b1033bb03cc5871522989e58aa7022c5de1875ee))
+
+
+### Bug Fixes
+
+* add missing comma in commands slice ([285b0e5](https://github.com/yemaney/z/commit/285b0e56df79e91bb44aecb04322e63c8021823f))
+
+
+### Code Refactoring
+
+* **cc:** drop params ([1111111](https://github.com/yemaney/z/commit/1111111111111111111111111111111111111111))
+`
+
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestPrependRelease(t *testing.T) {
+	section := "## [1.5.0](url) (2024-04-01)\n\n\n### Features\n\n* new\n"
+	existing := "# Changelog\n\n## [1.4.0](url) (2024-03-31)\n\n\n### Features\n\n* old\n"
+
+	testCases := []struct {
+		desc      string
+		changelog string
+		want      string
+	}{
+		{
+			desc:      "existing releases kept",
+			changelog: existing,
+			want:      "# Changelog\n\n" + section + "\n## [1.4.0](url) (2024-03-31)\n\n\n### Features\n\n* old\n",
+		},
+		{
+			desc:      "new changelog",
+			changelog: "",
+			want:      "# Changelog\n\n" + section,
+		},
+		{
+			desc:      "no releases yet",
+			changelog: "# Changelog\n",
+			want:      "# Changelog\n\n" + section,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got := prependRelease(tC.changelog, section)
+
+			if got != tC.want {
+				t.Errorf("got %q want %q", got, tC.want)
+			}
+		})
+	}
+
+	if !hasRelease(existing, "v1.4.0") || hasRelease(existing, "1.4") {
+		t.Errorf("existing release not detected")
+	}
+}
+
+func TestChangelog(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		to    string
+		tags  string
+		title string
+	}{
+		{desc: "Tagged release", to: "v1.1.0", tags: "v1.1.0", title: "## [1.1.0]"},
+		{desc: "Untagged head", to: "HEAD", tags: "", title: "## [Unreleased]"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			buffer, cli, _ := mockGitCLI(map[string]string{
+				"tag --list --merged " + tC.to + "^":         "v0.9.0\nssh/v1.2.0\nv1.0.0\nnightly",
+				"log --format=%H%x1f%B%x1e v1.0.0.." + tC.to: "aaa\x1ffeat: add create\n",
+				"tag --list " + tC.to:                        tC.tags,
+				"remote get-url origin":                      "git@github.com:yemaney/z.git",
+				"log -1 --format=%cs " + tC.to:               "2024-03-31",
+			})
+
+			if err := cli.changelog(changelogArgs{to: tC.to}); err != nil {
+				t.Fatal(err)
+			}
+
+			got := buffer.String()
+			if !strings.Contains(got, tC.title) || !strings.Contains(got, "add create") {
+				t.Errorf("got %q want a %s section with the commits since v1.0.0", got, tC.title)
+			}
+		})
+	}
+}
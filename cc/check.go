@@ -0,0 +1,269 @@
+package cc
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// checkFormats are the formats the results of the check command can be written in.
+var checkFormats = []string{"text", "json", "junit"}
+
+// fixupPrefixes start the headers of the commits that git commit --fixup and
+// --squash make, which are squashed into other commits before merging.
+var fixupPrefixes = []string{"fixup! ", "squash! ", "amend! "}
+
+// gitRevertPrefix starts the headers of the commits that git revert makes.
+const gitRevertPrefix = `Revert "`
+
+// checkConfig holds the settings of the check command: which commits are skipped
+// instead of checked.
+type checkConfig struct {
+	SkipMerges  bool     `yaml:"skipMerges"`
+	SkipFixups  bool     `yaml:"skipFixups"`
+	SkipReverts bool     `yaml:"skipReverts"`
+	SkipAuthors []string `yaml:"skipAuthors"`
+}
+
+// checkArgs contains arguments used for the check command
+type checkArgs struct {
+	revs   string
+	format string
+}
+
+// parseCheckArgs parses the revision range and the parameters of the check command.
+func parseCheckArgs(args []string) (checkArgs, error) {
+	cArgs := checkArgs{format: "text"}
+
+	for i := 0; i < len(args); i++ {
+		param := args[i]
+
+		if param != "format" {
+			if cArgs.revs != "" {
+				return cArgs, fmt.Errorf("unsupported parameter: %s", param)
+			}
+			cArgs.revs = param
+			continue
+		}
+
+		if i+1 >= len(args) {
+			return cArgs, fmt.Errorf("missing value for parameter: %s", param)
+		}
+		i++
+		cArgs.format = args[i]
+	}
+
+	if cArgs.revs == "" {
+		return cArgs, fmt.Errorf("missing revision range, such as origin/main..HEAD")
+	}
+
+	for _, f := range checkFormats {
+		if cArgs.format == f {
+			return cArgs, nil
+		}
+	}
+	return cArgs, fmt.Errorf("unsupported format: %s, must be one of: %s", cArgs.format, strings.Join(checkFormats, ", "))
+}
+
+// checkedCommit is a commit in the range along with the errors found in its
+// message, or the reason it was skipped.
+type checkedCommit struct {
+	SHA     string       `json:"sha"`
+	Author  string       `json:"author"`
+	Header  string       `json:"header"`
+	Status  string       `json:"status"`
+	Skipped string       `json:"skipped,omitempty"`
+	Errors  []*LintError `json:"errors,omitempty"`
+}
+
+// checkReport is the result of checking every commit in a range.
+type checkReport struct {
+	Range   string          `json:"range"`
+	Checked int             `json:"checked"`
+	Skipped int             `json:"skipped"`
+	Failed  int             `json:"failed"`
+	Commits []checkedCommit `json:"commits"`
+}
+
+// skipReason returns why a commit is skipped according to the check config, or
+// an empty string when it is checked.
+func (cfg *checkConfig) skipReason(authors []*regexp.Regexp, author, parents, header string) string {
+	if cfg.SkipMerges && len(strings.Fields(parents)) > 1 {
+		return "merge commit"
+	}
+
+	if cfg.SkipFixups {
+		for _, prefix := range fixupPrefixes {
+			if strings.HasPrefix(header, prefix) {
+				return "fixup commit"
+			}
+		}
+	}
+
+	if cfg.SkipReverts && strings.HasPrefix(header, gitRevertPrefix) {
+		return "revert commit"
+	}
+
+	for _, re := range authors {
+		if re.MatchString(author) {
+			return "author matches " + re.String()
+		}
+	}
+	return ""
+}
+
+// compileAuthors compiles the author patterns of the commits that are skipped.
+func (cfg *checkConfig) compileAuthors() ([]*regexp.Regexp, error) {
+	authors := make([]*regexp.Regexp, len(cfg.SkipAuthors))
+	for i, pattern := range cfg.SkipAuthors {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid check skipAuthors pattern %q: %w", pattern, err)
+		}
+		authors[i] = re
+	}
+	return authors, nil
+}
+
+// checkRange lints the message of every commit in the revision range, oldest first,
+// skipping the commits the check config says to.
+func (c *CLI) checkRange(revs string) (checkReport, error) {
+	report := checkReport{Range: revs}
+
+	authors, err := c.cfg.Check.compileAuthors()
+	if err != nil {
+		fmt.Fprintln(c.Out, err)
+		return report, err
+	}
+
+	out, err := c.ce.output("log", "--reverse", "--format=%H%x1f%an <%ae>%x1f%P%x1f%B%x1e", revs)
+	if err != nil {
+		fmt.Fprintf(c.Out, "Error reading commits in %s, is it a valid revision range?\n", revs)
+		return report, err
+	}
+
+	for _, record := range strings.Split(out, recordSep) {
+		fields := strings.SplitN(strings.TrimSpace(record), fieldSep, 4)
+		if len(fields) != 4 {
+			continue
+		}
+
+		cm := checkedCommit{SHA: fields[0], Author: fields[1], Status: "passed"}
+		message := fields[3]
+		if lines := cleanLines(message); len(lines) > 0 {
+			cm.Header = lines[0].text
+		}
+
+		if cm.Skipped = c.cfg.Check.skipReason(authors, cm.Author, fields[2], cm.Header); cm.Skipped != "" {
+			cm.Status = "skipped"
+			report.Skipped++
+		} else if cm.Errors = c.cfg.lintFormat(message); len(cm.Errors) > 0 {
+			cm.Status = "failed"
+			report.Failed++
+		}
+
+		report.Checked++
+		report.Commits = append(report.Commits, cm)
+	}
+
+	return report, nil
+}
+
+// writeCheckReport writes the report in the given format.
+func (c *CLI) writeCheckReport(report checkReport, format string) error {
+	switch format {
+	case "json":
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.Out, string(b))
+	case "junit":
+		b, err := xml.MarshalIndent(report.junit(), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.Out, xml.Header+string(b))
+	default:
+		for _, cm := range report.Commits {
+			if cm.Status != "failed" {
+				continue
+			}
+			fmt.Fprintf(c.Out, "%s \033[36;1m%s\033[0m\n", shortSHA(cm.SHA), cm.Header)
+			for _, err := range cm.Errors {
+				fmt.Fprintf(c.Out, "  %s\n", err)
+			}
+		}
+		fmt.Fprintf(c.Out, "Checked %d commits in %s: %d failed, %d skipped\n", report.Checked, report.Range, report.Failed, report.Skipped)
+	}
+	return nil
+}
+
+// shortSHA abbreviates a commit hash the way git log --oneline does.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// junitSuites is the root of a JUnit XML report.
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+// junitSuite is a JUnit XML test suite, with a test case for every commit.
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+// junitCase is a JUnit XML test case, which fails or is skipped along with a message.
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+// junitMessage is the message of a failed or skipped JUnit XML test case.
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// junit converts the report into a JUnit XML test suite named after the range,
+// with the errors of a commit in the text of its failure.
+func (r checkReport) junit() junitSuites {
+	suite := junitSuite{Name: "cc check " + r.Range, Tests: r.Checked, Failures: r.Failed, Skipped: r.Skipped}
+
+	for _, cm := range r.Commits {
+		tc := junitCase{Name: shortSHA(cm.SHA) + " " + cm.Header, ClassName: "cc.check"}
+
+		switch cm.Status {
+		case "skipped":
+			tc.Skipped = &junitMessage{Message: cm.Skipped}
+		case "failed":
+			errs := make([]string, len(cm.Errors))
+			for i, err := range cm.Errors {
+				errs[i] = err.Error()
+			}
+			tc.Failure = &junitMessage{
+				Message: cm.Errors[0].Message,
+				Type:    cm.Errors[0].Rule,
+				Text:    cm.SHA + "\n" + strings.Join(errs, "\n"),
+			}
+		}
+
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	return junitSuites{Suites: []junitSuite{suite}}
+}
@@ -0,0 +1,181 @@
+package cc
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// checkLog is the git log output of a range with a valid commit, a merge, a fixup,
+// a commit from a bot and a commit that does not follow the format.
+var checkLog = strings.Join([]string{
+	"aaaaaaaaaa\x1fAda <ada@example.com>\x1fp1\x1ffeat(cc): add check\n",
+	"bbbbbbbbbb\x1fAda <ada@example.com>\x1fp1 p2\x1fMerge branch 'main'\n",
+	"cccccccccc\x1fAda <ada@example.com>\x1fp1\x1ffixup! feat(cc): add check\n",
+	"dddddddddd\x1frenovate[bot] <bot@example.com>\x1fp1\x1fUpdate dependency\n",
+	"eeeeeeeeee\x1fAda <ada@example.com>\x1fp1\x1ffeature: report failures\n",
+}, "\x1e") + "\x1e"
+
+// checkOutputs are the git outputs of checkLog as the commits in origin/main..HEAD,
+// and of a revert made by git as the commit in HEAD~1..HEAD.
+var checkOutputs = map[string]string{
+	"log --reverse --format=%H%x1f%an <%ae>%x1f%P%x1f%B%x1e origin/main..HEAD": checkLog,
+	"log --reverse --format=%H%x1f%an <%ae>%x1f%P%x1f%B%x1e HEAD~1..HEAD": "ffffffffff\x1fAda <ada@example.com>\x1fp1\x1f" +
+		"Revert \"feat(cc): add check\"\n\nThis reverts commit aaaaaaaaaa.\n\x1e",
+}
+
+func TestParseCheckArgs(t *testing.T) {
+	testCases := []struct {
+		desc string
+		args []string
+		want checkArgs
+		err  bool
+	}{
+		{desc: "Range", args: []string{"origin/main..HEAD"}, want: checkArgs{"origin/main..HEAD", "text"}},
+		{desc: "Format", args: []string{"format", "junit", "HEAD~3..HEAD"}, want: checkArgs{"HEAD~3..HEAD", "junit"}},
+		{desc: "Missing range", args: []string{"format", "json"}, err: true},
+		{desc: "Unsupported format", args: []string{"HEAD", "format", "xml"}, err: true},
+		{desc: "Two ranges", args: []string{"HEAD~1", "HEAD"}, err: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := parseCheckArgs(tC.args)
+
+			if (err != nil) != tC.err {
+				t.Fatalf("got error %v", err)
+			}
+			if !tC.err && got != tC.want {
+				t.Errorf("got %+v want %+v", got, tC.want)
+			}
+		})
+	}
+}
+
+func TestCheckRange(t *testing.T) {
+	skipped := checkConfig{SkipMerges: true, SkipFixups: true, SkipReverts: true}
+	bots := skipped
+	bots.SkipAuthors = []string{`\[bot\]`}
+
+	testCases := []struct {
+		desc  string
+		revs  string
+		check checkConfig
+		want  []string
+		err   bool
+	}{
+		{
+			desc:  "Merges, fixups and bots skipped",
+			revs:  "origin/main..HEAD",
+			check: bots,
+			want:  []string{"passed", "skipped merge commit", "skipped fixup commit", `skipped author matches \[bot\]`, "failed type-enum"},
+		},
+		{
+			desc: "Nothing skipped",
+			revs: "origin/main..HEAD",
+			want: []string{"passed", "failed header-format", "failed type-enum", "failed header-format", "failed type-enum"},
+		},
+		{desc: "Reverts made by git skipped", revs: "HEAD~1..HEAD", check: skipped, want: []string{"skipped revert commit"}},
+		{desc: "Reverts made by git checked", revs: "HEAD~1..HEAD", want: []string{"failed type-enum"}},
+		{desc: "Invalid author pattern", revs: "origin/main..HEAD", check: checkConfig{SkipAuthors: []string{`[bot`}}, err: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			_, cli, _ := mockGitCLI(checkOutputs)
+			cli.cfg.Check = tC.check
+
+			report, err := cli.checkRange(tC.revs)
+			if (err != nil) != tC.err {
+				t.Fatalf("got error %v", err)
+			}
+			if tC.err {
+				return
+			}
+
+			var got []string
+			var skips, failures int
+			for _, cm := range report.Commits {
+				switch cm.Status {
+				case "skipped":
+					skips++
+					got = append(got, cm.Status+" "+cm.Skipped)
+				case "failed":
+					failures++
+					got = append(got, cm.Status+" "+cm.Errors[0].Rule)
+				default:
+					got = append(got, cm.Status)
+				}
+			}
+			if !reflect.DeepEqual(got, tC.want) {
+				t.Errorf("got %q want %q", got, tC.want)
+			}
+			if report.Checked != len(tC.want) || report.Skipped != skips || report.Failed != failures {
+				t.Errorf("got %d checked, %d skipped, %d failed", report.Checked, report.Skipped, report.Failed)
+			}
+		})
+	}
+}
+
+func TestWriteCheckReport(t *testing.T) {
+	_, cli, _ := mockGitCLI(checkOutputs)
+	report, err := cli.checkRange("origin/main..HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		buffer, cli, _ := mockCLI()
+
+		cli.writeCheckReport(report, "text")
+
+		got := buffer.String()
+		want := "ddddddd \033[36;1mUpdate dependency\033[0m\n" +
+			"  1:1: header must be written as <type>(<scope>): <subject> [header-format]\n" +
+			"eeeeeee \033[36;1mfeature: report failures\033[0m\n" +
+			"  1:1: type \"feature\" must be one of: build, ci, docs, feat, fix, refactor, test, chore [type-enum]\n" +
+			"Checked 5 commits in origin/main..HEAD: 2 failed, 2 skipped\n"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		buffer, cli, _ := mockCLI()
+
+		cli.writeCheckReport(report, "json")
+
+		var got checkReport
+		if err := json.Unmarshal(buffer.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Failed != report.Failed || got.Commits[4].SHA != "eeeeeeeeee" || got.Commits[4].Errors[0].Rule != "type-enum" {
+			t.Errorf("got %+v want %+v", got, report)
+		}
+	})
+
+	t.Run("JUnit", func(t *testing.T) {
+		buffer, cli, _ := mockCLI()
+
+		cli.writeCheckReport(report, "junit")
+
+		var got junitSuites
+		if err := xml.Unmarshal(buffer.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+
+		suite := got.Suites[0]
+		if suite.Tests != 5 || suite.Failures != 2 || suite.Skipped != 2 {
+			t.Errorf("got %+v", suite)
+		}
+
+		failure := suite.Cases[4].Failure
+		if failure == nil || failure.Type != "type-enum" || !strings.HasPrefix(failure.Text, "eeeeeeeeee\n") {
+			t.Errorf("got failure %+v want type-enum", failure)
+		}
+		if skipped := suite.Cases[1].Skipped; skipped == nil || skipped.Message != "merge commit" {
+			t.Errorf("got skipped %+v want merge commit", skipped)
+		}
+	})
+}
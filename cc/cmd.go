@@ -1,6 +1,7 @@
 package cc
 
 import (
+	"fmt"
 	"os"
 
 	Z "github.com/rwxrob/bonzai/z"
@@ -12,10 +13,10 @@ import (
 var Cmd = &Z.Cmd{
 	Name:     `cc`,
 	Summary:  `git commit in the style of conventional commits`,
-	Params:   []string{"signed", "breaking"},
-	Usage:    `[signed] [breaking]`,
+	Params:   ccParams,
+	Usage:    `[signed] [breaking] [yes] [edit] [pair|solo] [type <type>] [scope <scope>] [subject <subject>] [body <body>] [footer <footer>]`,
 	Comp:     compcmd.New(),
-	Commands: []*Z.Cmd{help.Cmd},
+	Commands: []*Z.Cmd{help.Cmd, lintCmd, checkCmd, logCmd, statsCmd, hookCmd, prepareCmd, changelogCmd, bumpCmd, amendCmd, revertCmd, splitCmd, draftCmd},
 	Description: `
 		The {{aka}} command provides the ability to make git commits in the style of conventional commits. (https://www.conventionalcommits.org)
 
@@ -26,19 +27,818 @@ var Cmd = &Z.Cmd{
 
 		signed		:	the commit will be signed <https://docs.github.com/en/authentication/managing-commit-signature-verification/signing-commits>
 
-		breaking	:	the commit message will start with: type! or type(scope)!
+		breaking	:	the commit message will start with: type! or type(scope)!, and a description of the breaking change is required
+
+		yes		:	commit without asking for confirmation
+
+		edit		:	write the body and footer in $VISUAL or $EDITOR instead of prompting for them
+
+		pair		:	choose co-authors to credit with Co-authored-by footers, remembered for the next commits of the repository
+
+		solo		:	stop crediting the co-authors chosen earlier
+
+		type		:	the type of the commit (ex feat), instead of prompting for it
+
+		scope		:	the scope of the commit, instead of prompting for it
+
+		subject		:	the subject of the commit, instead of prompting for it
+
+		body		:	the body of the commit, instead of prompting for it
+
+		footer		:	a footer of the commit (ex "Closes #12"), instead of prompting for them. May be repeated
+
+		Before prompting, {{aka}} checks that it is run inside a work tree and that
+		no merge or rebase is in progress. When nothing is staged, it offers to
+		stage changes with git add --patch, or every change to tracked files like
+		git commit -a does. When git fails to make the commit, its error output is
+		written as git writes it and {{aka}} exits with the same status.
+
+		When the commit is not made, because it was not confirmed, git failed or
+		the prompts were interrupted with Ctrl-C, what was entered is saved as a
+		draft in the git directory of the repository. The newest draft is offered
+		the next time {{aka}} is run, and restoring it pre-fills the prompts. Saved
+		drafts are managed with {{cmd "draft"}}.
+
+		Only the fields that are not passed as parameters are prompted for, so
+		passing all of them along with yes makes a commit without any prompts.
+
+		z cc type feat scope cc subject "add lint command" body "" footer "" yes
+
+		With edit, the editor is opened on the message built so far along with
+		comments describing the format, as git does. Lines starting with # are
+		removed, and the last paragraph becomes the footer when it is made of
+		trailers such as "Closes #12", so bodies of several paragraphs and
+		multiple footers can be written. The header may be edited as well.
+
+		Footers are prompted for one at a time until an empty one is entered,
+		and are written as <token>: <value> or <token> #<value>, such as:
+
+		{{ indent 4 "Closes #12" }}
+		{{ indent 4 "Refs: #7" }}
+		{{ indent 4 "Reviewed-by: Name <email>" }}
+		{{ indent 4 "Co-authored-by: Name <email>" }}
+		{{ indent 4 "Signed-off-by: Name <email>" }}
+		{{ indent 4 "BREAKING CHANGE: description" }}
+
+		They are added to the commit as trailers that git interpret-trailers
+		understands, so Closes #12 is written as Closes: #12 and BREAKING CHANGE
+		as its BREAKING-CHANGE synonym. When breaking is set and there is no
+		BREAKING CHANGE footer, a description of the breaking change is prompted for,
+		and no commit is made without one.
+
+		The types that can be chosen, their descriptions and their order can be
+		configured using the **conf** command, and for a single repository with a
+		.cc.yaml file at its root that takes precedence over conf. Both are YAML
+		files with the following schema (in conf it is nested under cc).
+
+		---
+
+		types:
+
+		{{ indent 2 "- name: feat" }}
+		{{ indent 4 "description: A new feature" }}
+		{{ indent 2 "- name: perf" }}
+		{{ indent 4 "description: A code change that improves performance" }}
+
+		scopes:
+
+		{{ indent 2 "- glob: packages/*/**" }}
+		{{ indent 4 "scope: $1" }}
+		{{ indent 2 "- glob: \"**/*.md\"" }}
+		{{ indent 4 "scope: docs" }}
+
+		allowedScopes:
+
+		{{ indent 2 "- ssh" }}
+		{{ indent 2 "- aws" }}
+
+		branches:
+
+		{{ indent 2 "- pattern: ^(?P<type>[a-z]+)/(?P<ticket>[A-Z]+-[0-9]+)" }}
+
+		coauthors:
+
+		{{ indent 2 "- Jane Doe <jane@example.com>" }}
+		---
+
+		When prompting for a scope, the scopes of the staged files are suggested as
+		numbered choices. Each file gets the scope of the first scopes rule whose glob
+		matches it, where ** matches any number of directories and $1, $2, ... are
+		replaced with what the wildcards in the glob matched. Files that match no rule
+		are scoped to their Go package or their top-level directory.
+
+		The type, the scope and a Refs footer for a ticket are pre-filled from the
+		name of the current branch by the first branches pattern that matches it,
+		with the named groups type, scope and ticket, and can still be changed at
+		the prompts. By default branches named like feat/PROJ-123-short-desc and
+		fix/payments/short-desc are understood.
+
+		With pair, the co-authors are chosen from the coauthors roster in the config,
+		written as Name <email>, followed by the authors of recent commits. They
+		are entered as numbers, such as "0 2", or as names that are fuzzy matched,
+		separated by commas, and a Co-authored-by footer is added for each. The
+		choice is remembered in the git directory of the repository, so later
+		commits, including those made through the prepare-commit-msg hook, credit
+		the same co-authors until solo is passed. Every commit crediting them
+		says so.
+
+		When the repository has a commitlint configuration, such as
+		.commitlintrc.json or commitlint.config.js, its type-enum, scope-enum,
+		subject-case and header-max-length rules are applied before the config,
+		along with those of @commitlint/config-conventional when it is extended.
+		JavaScript configurations are not run, so they must export an object made
+		only of literals, written in place or declared as a variable, and a
+		configuration whose rules do not parse is reported once without applying
+		any of them.
+		With scope-enum or allowedScopes in the config, only the scopes listed are
+		offered and accepted.
+
+		Before asking for confirmation, the writing guidelines the message does not
+		follow are listed, with an offer to fix the ones that can be: a header
+		longer than headerMaxLength, a subject ending with a period, starting with
+		a capital letter or not in the imperative mood, and body lines longer than
+		bodyWrap. Each rule can be turned off under style.
+
+		The message is laid out with the template config, which names one of the
+		conventional (default), angular or gitmoji presets or is a Go text/template
+		rendered with the fields of the commit: .Type, .Scope, .Breaking,
+		.Subject, .Body, .Footers and .Footer "<token>" for the value of a footer.
+		The gitmoji function returns the emoji of a type, which may start the
+		header. A template whose messages do not start with a conventional commit
+		header of their type is reported and the conventional format used instead.
+
+		When run in a terminal, the type and the scope are chosen in a full screen
+		picker instead, moving with the arrow keys and typing to filter the choices.
+		Co-authors are chosen in it one at a time, until Esc is pressed.
+		The scope picker also offers the scopes used before in the repository, and
+		a scope that matches none of them can be typed in. Esc keeps the current
+		value. The numbered prompts are used when the input is not a terminal.
 
 		`,
 	Call: func(caller *Z.Cmd, args ...string) error {
 		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+		cli.loadConfig()
 		cli.parseParams(args)
-		cli.writeTypesPrompt()
-		cli.readType()
-		cli.readScope()
-		cli.readSubject()
-		cli.readBodyAndFooter()
+
+		if err := cli.checkRepository(); err != nil {
+			os.Exit(1)
+		}
+		if err := cli.checkStaged(); err != nil {
+			os.Exit(1)
+		}
+
+		cli.keepDraftOnInterrupt()
+		cli.readBranch()
+		cli.offerDraft()
+		cli.readCoauthors()
+		if err := cli.readMissingFields(); err != nil {
+			os.Exit(1)
+		}
 		cli.buildMessage()
-		cli.makeCommit()
+		if err := cli.makeCommit(); err != nil {
+			os.Exit(exitStatus(err))
+		}
+		return nil
+	},
+}
+
+var lintCmd = &Z.Cmd{
+	Name:     `lint`,
+	Summary:  `check that a commit message follows the conventional commit format`,
+	Usage:    `[file|-|message]`,
+	Comp:     compcmd.New(),
+	Commands: []*Z.Cmd{help.Cmd},
+	Description: `
+		The {{aka}} command checks a commit message against the same format that
+		{{cmd "cc"}} writes and exits with a non-zero status when it does not conform,
+		so that it can be used to gate commits.
+
+		The message is read from the file passed as the only argument, from stdin
+		when there are no arguments or the argument is -, and otherwise the arguments
+		are the message itself. Lines starting with # are ignored just like git does.
+
+		Every problem is reported with its line and column:
+
+		1:1: type "feature" must be one of: build, ci, docs, feat, fix, refactor, test, chore [type-enum]
+
+		Scopes outside the ones allowed by commitlint's scope-enum or allowedScopes
+		are reported, and so are headers that break the subject-case or
+		header-max-length rules of a commitlint configuration set at level 2.
+
+		Messages written by git itself, such as merges and fixups, are not checked.
+		`,
+	Call: func(caller *Z.Cmd, args ...string) error {
+		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+		cli.loadConfig()
+
+		message, err := readMessage(os.Stdin, args)
+		if err != nil {
+			fmt.Fprintf(cli.Out, "Error reading commit message: %s\n", err)
+			os.Exit(1)
+		}
+
+		if !cli.lintMessage(message) {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+var checkCmd = &Z.Cmd{
+	Name:     `check`,
+	Summary:  `check that every commit in a range follows the conventional commit format`,
+	Usage:    `<rev-range> [format text|json|junit]`,
+	Params:   []string{"format"},
+	Comp:     compcmd.New(),
+	Commands: []*Z.Cmd{help.Cmd},
+	Description: `
+		The {{aka}} command lints the message of every commit in a revision range,
+		such as origin/main..HEAD, with the same rules as {{cmd "lint"}}, and exits
+		with a non-zero status when any of them does not conform, so that it can
+		gate pull requests in CI. Each failing commit is reported with its SHA and
+		the rules it broke.
+
+		format	:	text (default), json, or junit for JUnit XML that CI systems
+				can turn into annotations
+
+		Merge commits, the fixup!, squash! and amend! commits and the Revert "..."
+		commits made by git are skipped, as {{cmd "lint"}} accepts them, along
+		with the commits of authors matching a pattern, such as bots. What is
+		skipped is configured under check:
+
+		---
+
+		check:
+
+		{{ indent 2 "skipMerges: true" }}
+		{{ indent 2 "skipFixups: false" }}
+		{{ indent 2 "skipReverts: true" }}
+		{{ indent 2 "skipAuthors:" }}
+		{{ indent 4 "- '\\[bot\\]'" }}
+		---
+
+		Authors are matched as "Name <email>".
+		`,
+	Call: func(caller *Z.Cmd, args ...string) error {
+		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+		cli.loadConfig()
+
+		cArgs, err := parseCheckArgs(args)
+		if err != nil {
+			fmt.Fprintln(cli.Out, err)
+			os.Exit(1)
+		}
+
+		report, err := cli.checkRange(cArgs.revs)
+		if err != nil {
+			os.Exit(1)
+		}
+
+		if err := cli.writeCheckReport(report, cArgs.format); err != nil {
+			fmt.Fprintf(cli.Out, "Error writing check report: %s\n", err)
+			os.Exit(1)
+		}
+		if report.Failed > 0 {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+var logCmd = &Z.Cmd{
+	Name:     `log`,
+	Summary:  `list the conventional commits of a range, filtered by type, scope and more`,
+	Usage:    `[<rev-range>] [type <type>]... [scope <scope>]... [breaking] [author <pattern>] [since <date>] [until <date>] [format text|json]`,
+	Params:   []string{"type", "scope", "breaking", "author", "since", "until", "format"},
+	Comp:     compcmd.New(),
+	Commands: []*Z.Cmd{help.Cmd},
+	Description: `
+		The {{aka}} command lists the conventional commits of a revision range,
+		HEAD by default, newest first, with their short SHA, date, header and
+		author. Commits that are not conventional commits are left out.
+
+		type		:	only commits of the type, may be repeated to list several types
+
+		scope		:	only commits with the scope, may be repeated to list several scopes
+
+		breaking	:	only breaking changes
+
+		author		:	only commits whose author matches the pattern, as with git log --author
+
+		since		:	only commits more recent than the date, as with git log --since
+
+		until		:	only commits older than the date, as with git log --until
+
+		format		:	text (default) or json
+
+		{{ indent 4 "z cc log origin/main..HEAD type feat type fix scope ssh" }}
+		{{ indent 4 "z cc log breaking since 2026-01-01" }}
+		`,
+	Call: func(caller *Z.Cmd, args ...string) error {
+		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+		cli.loadConfig()
+
+		hArgs, err := parseHistoryArgs(args)
+		if err != nil {
+			fmt.Fprintln(cli.Out, err)
+			os.Exit(1)
+		}
+
+		commits, err := cli.readHistory(hArgs)
+		if err != nil {
+			os.Exit(1)
+		}
+		return cli.writeLog(commits, hArgs.format)
+	},
+}
+
+var statsCmd = &Z.Cmd{
+	Name:     `stats`,
+	Summary:  `summarize the types and scopes of the conventional commits per author and per month`,
+	Usage:    `[<rev-range>] [type <type>]... [scope <scope>]... [breaking] [author <pattern>] [since <date>] [until <date>] [format text|json]`,
+	Params:   []string{"type", "scope", "breaking", "author", "since", "until", "format"},
+	Comp:     compcmd.New(),
+	Commands: []*Z.Cmd{help.Cmd},
+	Description: `
+		The {{aka}} command counts the conventional commits of a revision range,
+		HEAD by default, by type and by scope, per author and per month. The
+		commits are selected with the same parameters as {{cmd "log"}}, and the
+		counts are written as tables, or as JSON with format json.
+
+		{{ indent 4 "z cc stats since \"1 year ago\"" }}
+		{{ indent 4 "z cc stats v1.0.0..HEAD format json" }}
+		`,
+	Call: func(caller *Z.Cmd, args ...string) error {
+		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+		cli.loadConfig()
+
+		hArgs, err := parseHistoryArgs(args)
+		if err != nil {
+			fmt.Fprintln(cli.Out, err)
+			os.Exit(1)
+		}
+
+		commits, err := cli.readHistory(hArgs)
+		if err != nil {
+			os.Exit(1)
+		}
+		return cli.writeStats(summarize(hArgs.revs, commits), hArgs.format)
+	},
+}
+
+var hookCmd = &Z.Cmd{
+	Name:     `hook`,
+	Summary:  `manage the git hooks that enforce conventional commits`,
+	Comp:     compcmd.New(),
+	Commands: []*Z.Cmd{help.Cmd, hookInstallCmd, hookUninstallCmd, hookStatusCmd},
+	Description: `
+		The {{aka}} command manages git hooks in the current repository that call
+		back into cc. The commit-msg hook calls {{cmd "lint"}}, so that even commits
+		made with plain git commit have to follow the conventional commit format.
+		The prepare-commit-msg hook calls prepare, so that git commit prompts for
+		the fields of a conventional commit and opens the message in the editor.
+
+		The install and uninstall commands take the names of the hooks, and manage
+		the commit-msg hook when none are given:
+
+		{{ indent 4 "z cc hook install commit-msg prepare-commit-msg" }}
+
+		The hooks are written into the directory git runs hooks from, which is
+		.git/hooks unless core.hooksPath is set. A hook that is already there is
+		kept with a .chained suffix and run before cc instead of being
+		overwritten, and is restored on uninstall.
+		`,
+}
+
+var hookInstallCmd = &Z.Cmd{
+	Name:     `install`,
+	Summary:  `install git hooks in the current repository`,
+	Usage:    `[commit-msg] [prepare-commit-msg]`,
+	Params:   hookNames,
+	Comp:     compcmd.New(),
+	Commands: []*Z.Cmd{help.Cmd},
+	Call: func(caller *Z.Cmd, args ...string) error {
+		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+
+		names, err := parseHookNames(args)
+		if err != nil {
+			fmt.Fprintln(cli.Out, err)
+			os.Exit(1)
+		}
+
+		dir, err := cli.hooksDir()
+		if err != nil {
+			os.Exit(1)
+		}
+
+		// caller is cc hook install, the hook calls back into cc
+		command := append([]string{Z.ExePath}, caller.Caller.Caller.PathNames()...)
+		for _, name := range names {
+			if err := cli.installHook(dir, name, command); err != nil {
+				os.Exit(1)
+			}
+		}
+		return nil
+	},
+}
+
+var hookUninstallCmd = &Z.Cmd{
+	Name:     `uninstall`,
+	Summary:  `uninstall git hooks from the current repository`,
+	Usage:    `[commit-msg] [prepare-commit-msg]`,
+	Params:   hookNames,
+	Comp:     compcmd.New(),
+	Commands: []*Z.Cmd{help.Cmd},
+	Call: func(caller *Z.Cmd, args ...string) error {
+		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+
+		names, err := parseHookNames(args)
+		if err != nil {
+			fmt.Fprintln(cli.Out, err)
+			os.Exit(1)
+		}
+
+		dir, err := cli.hooksDir()
+		if err != nil {
+			os.Exit(1)
+		}
+
+		for _, name := range names {
+			if err := cli.uninstallHook(dir, name); err != nil {
+				os.Exit(1)
+			}
+		}
+		return nil
+	},
+}
+
+var hookStatusCmd = &Z.Cmd{
+	Name:     `status`,
+	Summary:  `show whether the git hooks are installed`,
+	Commands: []*Z.Cmd{help.Cmd},
+	Call: func(caller *Z.Cmd, args ...string) error {
+		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+
+		dir, err := cli.hooksDir()
+		if err != nil {
+			os.Exit(1)
+		}
+
+		for _, name := range hookNames {
+			cli.hookStatus(dir, name)
+		}
+		return nil
+	},
+}
+
+var changelogCmd = &Z.Cmd{
+	Name:     `changelog`,
+	Summary:  `generate a changelog from the conventional commits in the git history`,
+	Usage:    `[from <rev>] [to <rev>] [version <version>] [component <name>] [write]`,
+	Params:   []string{"from", "to", "version", "component", "write"},
+	Comp:     compcmd.New(),
+	Commands: []*Z.Cmd{help.Cmd},
+	Description: `
+		The {{aka}} command parses every conventional commit between two revisions
+		and renders them as a Markdown release section, grouped into Features, Bug
+		Fixes, Build System and so on. Breaking changes are listed in a section of
+		their own, and each entry links to its commit and the heading links to the
+		comparison between the two revisions.
+
+		from	:	the revision to start after, defaults to the highest semantic
+				version tag before to, as chosen by bump
+
+		to		:	the revision to end at, defaults to HEAD
+
+		version	:	the version of the release, defaults to to when it is a tag
+
+		component	:	only list the commits of a component of a monorepo, starting
+				after its latest tag, see below
+
+		write	:	prepend the release to the CHANGELOG.md in the current directory,
+				or at the path of the component, instead of printing it, leaving
+				older releases untouched
+
+		The section a type is listed under can be set with section in the types
+		configuration. Types without a section, other than the well known ones, are
+		left out of the changelog.
+
+		Components are the separately versioned parts of a monorepo, such as Go
+		modules in subdirectories. A commit belongs to a component when its scope
+		is one of the component's scopes or when it touches files under its path,
+		and its versions are tagged with its tag prefix, such as ssh/v1.2.0. The
+		path defaults to the name, the tag prefix to the path followed by a slash
+		and the scopes to the name.
+
+		---
+
+		components:
+
+		{{ indent 2 "- name: ssh" }}
+		{{ indent 4 "path: ssh" }}
+		{{ indent 4 "tagPrefix: ssh/" }}
+		{{ indent 4 "scopes: [ssh, sshd]" }}
+		---
+		`,
+	Call: func(caller *Z.Cmd, args ...string) error {
+		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+		cli.loadConfig()
+
+		cArgs, err := parseChangelogArgs(args)
+		if err != nil {
+			fmt.Fprintln(cli.Out, err)
+			os.Exit(1)
+		}
+
+		if err := cli.changelog(cArgs); err != nil {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+var bumpCmd = &Z.Cmd{
+	Name:     `bump`,
+	Summary:  `work out the next semantic version from the commits since the latest release`,
+	Usage:    `[pre <id>] [component <name>] [tag] [signed]`,
+	Params:   []string{"pre", "component", "tag", "signed"},
+	Comp:     compcmd.New(),
+	Commands: []*Z.Cmd{help.Cmd},
+	Description: `
+		The {{aka}} command finds the latest semantic version tag on the current
+		branch, parses every commit since then and prints the next version. Breaking
+		changes bump the major version, features the minor version and fixes the
+		patch version. Before 1.0.0 breaking changes bump the minor version instead.
+		Prereleases tagged after the latest release, such as v1.0.0-rc.1, are
+		continued with pre or released as v1.0.0 without it, unless the commits
+		call for a higher version. When no commit requires a release, the command says so and exits with a
+		non-zero status.
+
+		pre		:	make the next version a prerelease with the given identifier,
+				numbered after any existing ones (ex pre rc gives v1.2.0-rc.2
+				after v1.2.0-rc.1)
+
+		component	:	work out the next version of a component of a monorepo from
+				its commits and its tags, such as ssh/v1.3.0 (see the help of
+				changelog for configuring components)
+
+		tag		:	create an annotated tag for the next version
+
+		signed	:	sign the tag <https://docs.github.com/en/authentication/managing-commit-signature-verification/signing-tags>
+		`,
+	Call: func(caller *Z.Cmd, args ...string) error {
+		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+		cli.loadConfig()
+
+		bArgs, err := parseBumpArgs(args)
+		if err != nil {
+			fmt.Fprintln(cli.Out, err)
+			os.Exit(1)
+		}
+
+		if err := cli.bump(bArgs); err != nil {
+			os.Exit(exitStatus(err))
+		}
+		return nil
+	},
+}
+
+var amendCmd = &Z.Cmd{
+	Name:     `amend`,
+	Summary:  `reword the message of an existing commit as a conventional commit`,
+	Usage:    `[<rev>] [signed] [breaking] [yes] [edit] [type <type>] [scope <scope>] [subject <subject>] [body <body>] [footer <footer>]`,
+	Comp:     compcmd.New(),
+	Commands: []*Z.Cmd{help.Cmd},
+	Description: `
+		The {{aka}} command parses the message of HEAD, or of the given commit,
+		back into its type, scope, subject, body and footers, and prompts for each
+		of them with the current value shown in brackets:
+
+		{{ indent 4 "Enter a subject [add lint command]: " }}
+
+		Entering nothing keeps the current value, and - removes the scope, the body
+		or the footers. The parameters are the same as for making a new commit and
+		replace the current value instead of prompting for it, so a typo in a scope
+		can be fixed with:
+
+		{{ indent 4 "z cc amend scope lint yes" }}
+
+		The last commit is amended with git commit --amend, leaving out any staged
+		changes. An older commit is reworded with an amend! commit followed by an
+		automated git rebase --autosquash, which rewrites every commit after it.
+		`,
+	Call: func(caller *Z.Cmd, args ...string) error {
+		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+		cli.loadConfig()
+
+		rev, params := splitAmendArgs(args)
+		if err := cli.readCommit(rev); err != nil {
+			os.Exit(1)
+		}
+
+		cli.parseParams(params)
+		if err := cli.checkRepository(); err != nil {
+			os.Exit(1)
+		}
+
+		if err := cli.readMissingFields(); err != nil {
+			os.Exit(1)
+		}
+		cli.buildMessage()
+		if err := cli.makeCommit(); err != nil {
+			os.Exit(exitStatus(err))
+		}
+		return nil
+	},
+}
+
+var revertCmd = &Z.Cmd{
+	Name:     `revert`,
+	Summary:  `revert commits with conventional revert commits`,
+	Usage:    `<rev>... [signed] [yes]`,
+	Comp:     compcmd.New(),
+	Commands: []*Z.Cmd{help.Cmd},
+	Description: `
+		The {{aka}} command reverts each of the given commits in turn with git
+		revert --no-commit, and commits the revert with the header of the reverted
+		commit as its subject, a body naming the commit and a Refs footer:
+
+		{{ indent 4 "revert: feat(ssh): add agent forwarding" }}
+		{{ indent 4 "" }}
+		{{ indent 4 "This reverts commit 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b." }}
+		{{ indent 4 "" }}
+		{{ indent 4 "Refs: 1a2b3c4" }}
+
+		The message is confirmed before committing unless yes is passed, and the
+		commit is signed with signed. Nothing may be staged beforehand, since it
+		would be committed along with the revert. When a commit cannot be reverted
+		cleanly, {{aka}} stops so that the conflicts can be resolved.
+
+		The revert type is accepted by {{cmd "lint"}} and {{cmd "check"}} without
+		being configured. {{cmd "changelog"}} and {{cmd "bump"}} leave out the
+		commits reverted within the same release along with their reverts.
+		`,
+	Call: func(caller *Z.Cmd, args ...string) error {
+		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+		cli.loadConfig()
+
+		revs, params := splitRevertArgs(args)
+		cli.parseParams(params)
+		if err := cli.checkRepository(); err != nil {
+			os.Exit(1)
+		}
+		if err := cli.revert(revs); err != nil {
+			os.Exit(exitStatus(err))
+		}
+		return nil
+	},
+}
+
+var splitCmd = &Z.Cmd{
+	Name:     `split`,
+	Summary:  `split the staged changes into a conventional commit per scope`,
+	Usage:    `[signed] [yes] [edit] [pair|solo]`,
+	Comp:     compcmd.New(),
+	Commands: []*Z.Cmd{help.Cmd},
+	Description: `
+		The {{aka}} command groups the staged files by their scope, the same way
+		the scopes of the staged files are suggested, and makes a commit for each
+		group in turn with git commit -- <paths>. The fields of each commit are
+		prompted for as with {{cmd "cc"}}, with the scope of the group pre-filled,
+		and a group whose commit is not confirmed stays staged. When the prompts
+		are given up, such as when no type is chosen, the commits already made are
+		kept and the groups left stay staged.
+
+		Since git commit -- <paths> commits the files as they are in the work
+		tree, {{aka}} refuses to run when a staged file also has unstaged changes.
+		When git fails to make one of the commits, the commits made before it are
+		undone with git reset --soft, leaving the index as it was before splitting.
+		`,
+	Call: func(caller *Z.Cmd, args ...string) error {
+		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+		cli.loadConfig()
+		cli.parseParams(args)
+
+		if err := cli.checkRepository(); err != nil {
+			os.Exit(1)
+		}
+		if err := cli.split(); err != nil {
+			os.Exit(exitStatus(err))
+		}
+		return nil
+	},
+}
+
+var prepareCmd = &Z.Cmd{
+	Name:     `prepare`,
+	Summary:  `prompt for a conventional commit from the prepare-commit-msg hook`,
+	Usage:    `<msgfile> [<source> [<commit>]]`,
+	Commands: []*Z.Cmd{help.Cmd},
+	Description: `
+		The {{aka}} command is called by the prepare-commit-msg hook that
+		{{cmd "hook"}} installs, with the arguments git passes to the hook. It
+		runs the same prompts as making a commit, and writes the message into
+		the message file instead of committing, so that git commit and the commit
+		buttons of editors still get a conventional commit message.
+
+		Nothing is done when git already supplied a message, such as with -m,
+		for merges and squashes or when amending, or when there is no terminal to
+		prompt on. The prompts are read from the terminal, as git does not
+		connect hooks to it.
+		`,
+	Call: func(caller *Z.Cmd, args ...string) error {
+		if len(args) == 0 {
+			cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+			fmt.Fprintln(cli.Out, "Missing commit message file")
+			os.Exit(1)
+		}
+
+		if len(args) > 1 && suppliesMessage(args[1]) {
+			return nil
+		}
+
+		tty, err := os.OpenFile(ttyPath, os.O_RDWR, 0)
+		if err != nil {
+			return nil
+		}
+		defer tty.Close()
+
+		cli := NewCLI(tty, tty, &CCExecutor{})
+		cli.loadConfig()
+
+		if err := cli.prepareMessage(args[0]); err != nil {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+var draftCmd = &Z.Cmd{
+	Name:     `draft`,
+	Summary:  `manage the drafts of commits that were not made`,
+	Comp:     compcmd.New(),
+	Commands: []*Z.Cmd{help.Cmd, draftListCmd, draftShowCmd, draftDropCmd},
+	Description: `
+		The {{aka}} command manages the drafts cc saves in the git directory of
+		the current repository when a commit is not made, because it was not
+		confirmed, git failed or the prompts were interrupted with Ctrl-C. The
+		newest draft is offered the next time a commit is made.
+
+		Drafts are named after the time they were saved, and show and drop use
+		the newest draft when no name is given.
+		`,
+}
+
+var draftListCmd = &Z.Cmd{
+	Name:     `list`,
+	Summary:  `list the saved drafts, newest first`,
+	Commands: []*Z.Cmd{help.Cmd},
+	Call: func(caller *Z.Cmd, args ...string) error {
+		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+
+		if err := cli.listDrafts(); err != nil {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+var draftShowCmd = &Z.Cmd{
+	Name:     `show`,
+	Summary:  `show the commit message of a saved draft`,
+	Usage:    `[<draft>]`,
+	Commands: []*Z.Cmd{help.Cmd},
+	Call: func(caller *Z.Cmd, args ...string) error {
+		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+
+		id := ""
+		if len(args) > 0 {
+			id = args[0]
+		}
+
+		if err := cli.showDraft(id); err != nil {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+var draftDropCmd = &Z.Cmd{
+	Name:     `drop`,
+	Summary:  `delete a saved draft, or all of them`,
+	Usage:    `[<draft>|all]`,
+	Commands: []*Z.Cmd{help.Cmd},
+	Call: func(caller *Z.Cmd, args ...string) error {
+		cli := NewCLI(os.Stdout, os.Stdin, &CCExecutor{})
+
+		id := ""
+		if len(args) > 0 {
+			id = args[0]
+		}
+
+		if err := cli.dropDraft(id); err != nil {
+			os.Exit(1)
+		}
 		return nil
 	},
 }
@@ -0,0 +1,347 @@
+package cc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// commitlintFiles are the files commitlint reads its configuration from, in the
+// order it looks for them. The configuration in package.json is under commitlint.
+var commitlintFiles = []string{
+	"package.json",
+	".commitlintrc",
+	".commitlintrc.json",
+	".commitlintrc.yaml",
+	".commitlintrc.yml",
+	".commitlintrc.js",
+	".commitlintrc.cjs",
+	"commitlint.config.js",
+	"commitlint.config.cjs",
+	"commitlint.config.mjs",
+}
+
+// configConventional is the name of the shared configuration most commitlint
+// configurations extend.
+const configConventional = "@commitlint/config-conventional"
+
+// configConventionalRules are the rules of @commitlint/config-conventional that cc
+// supports.
+var configConventionalRules = map[string][]interface{}{
+	"type-enum":         {2, "always", []interface{}{"build", "chore", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test"}},
+	"subject-case":      {2, "never", []interface{}{"sentence-case", "start-case", "pascal-case", "upper-case"}},
+	"header-max-length": {2, "always", 100},
+}
+
+// conventionalDescriptions describe the types of @commitlint/config-conventional,
+// for types that are not configured with a description.
+var conventionalDescriptions = map[string]string{
+	"build":    "Changes that affect the build system or external dependencies",
+	"chore":    "Other changes that don't modify src or test files",
+	"ci":       "Changes to our CI configuration files and scripts",
+	"docs":     "Documentation only changes",
+	"feat":     "A new feature",
+	"fix":      "A bug fix",
+	"perf":     "A code change that improves performance",
+	"refactor": "A code change that neither fixes a bug nor adds a feature",
+	"revert":   "Reverts a previous commit",
+	"style":    "Changes that do not affect the meaning of the code (white-space, formatting, etc)",
+	"test":     "Adding missing tests or correcting existing tests",
+}
+
+// commitlintConfig is the part of a commitlint configuration cc understands: the
+// shared configurations it extends and its rules, each written as
+// [level, applicable, value] where level 0 disables the rule, 1 warns and 2 errors.
+type commitlintConfig struct {
+	Extends interface{}              `yaml:"extends"`
+	Rules   map[string][]interface{} `yaml:"rules"`
+}
+
+// parseCommitlint parses the commitlint configuration in the file named name. JSON
+// and YAML files are parsed as YAML, and JavaScript files as long as they export
+// an object literal made only of literals.
+func parseCommitlint(name string, b []byte) (*commitlintConfig, error) {
+	if name == "package.json" {
+		var pkg struct {
+			Commitlint *commitlintConfig `yaml:"commitlint"`
+		}
+		if err := yaml.Unmarshal(b, &pkg); err != nil {
+			return nil, err
+		}
+		return pkg.Commitlint, nil
+	}
+
+	if ext := filepath.Ext(name); ext == ".js" || ext == ".cjs" || ext == ".mjs" {
+		obj, err := exportedJSObject(string(b))
+		if err != nil {
+			return nil, err
+		}
+		b = obj
+	}
+
+	cl := &commitlintConfig{}
+	if err := yaml.Unmarshal(b, cl); err != nil {
+		return nil, err
+	}
+	return cl, nil
+}
+
+// extends returns the names of the shared configurations the configuration extends,
+// which is a name or a list of names.
+func (cl *commitlintConfig) extends() []string {
+	switch v := cl.Extends.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var names []string
+		for _, name := range v {
+			names = append(names, fmt.Sprint(name))
+		}
+		return names
+	}
+	return nil
+}
+
+// commitlintRule is a commitlint rule as [level, applicable, value].
+type commitlintRule struct {
+	level  int
+	always bool
+	value  interface{}
+}
+
+// parseRule parses the commitlint rule named name, whose applicable defaults to
+// always. The values of the rules cc applies must have the type commitlint expects,
+// so that a configuration cc does not understand is reported instead of applied.
+func parseRule(name string, rule []interface{}) (commitlintRule, error) {
+	if len(rule) == 0 {
+		return commitlintRule{}, fmt.Errorf("rule %s has no level", name)
+	}
+
+	level, ok := rule[0].(int)
+	if !ok || level < 0 || level > 2 {
+		return commitlintRule{}, fmt.Errorf("rule %s has level %v, must be 0, 1 or 2", name, rule[0])
+	}
+
+	r := commitlintRule{level: level, always: true}
+	if len(rule) > 1 {
+		if rule[1] != "always" && rule[1] != "never" {
+			return commitlintRule{}, fmt.Errorf("rule %s has applicable %v, must be always or never", name, rule[1])
+		}
+		r.always = rule[1] == "always"
+	}
+	if len(rule) > 2 {
+		r.value = rule[2]
+	}
+	if level == 0 {
+		return r, nil
+	}
+
+	switch name {
+	case "type-enum", "scope-enum", "subject-case":
+		if _, ok := r.strings(); !ok {
+			return commitlintRule{}, fmt.Errorf("rule %s has value %v, must be a list of strings", name, r.value)
+		}
+	case "header-max-length":
+		if _, ok := r.value.(int); !ok {
+			return commitlintRule{}, fmt.Errorf("rule %s has value %v, must be a number", name, r.value)
+		}
+	}
+	return r, nil
+}
+
+// strings returns the value of the rule as a list of strings, which is a single
+// string for some rules, and whether it is one.
+func (r commitlintRule) strings() ([]string, bool) {
+	switch v := r.value.(type) {
+	case string:
+		return []string{v}, true
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, s := range v {
+			str, ok := s.(string)
+			if !ok {
+				return nil, false
+			}
+			values[i] = str
+		}
+		return values, true
+	}
+	return nil, false
+}
+
+// applyCommitlint applies the rules of a commitlint configuration and of the
+// shared configurations it extends: type-enum sets the types, scope-enum the
+// allowed scopes, and subject-case and header-max-length the style rules, which
+// lint also enforces when their level is error. Other rules are left to commitlint.
+// It returns the shared configurations that cannot be extended, and an error
+// without applying any rule when one of them does not parse.
+func (cfg *config) applyCommitlint(cl *commitlintConfig) ([]string, error) {
+	var unsupported []string
+	rules := map[string][]interface{}{}
+
+	for _, name := range cl.extends() {
+		if name != configConventional {
+			unsupported = append(unsupported, name)
+			continue
+		}
+		for rule, value := range configConventionalRules {
+			rules[rule] = value
+		}
+	}
+	for rule, value := range cl.Rules {
+		rules[rule] = value
+	}
+
+	parsed := map[string]commitlintRule{}
+	for name, value := range rules {
+		rule, err := parseRule(name, value)
+		if err != nil {
+			return nil, err
+		}
+		parsed[name] = rule
+	}
+
+	for name, rule := range parsed {
+		switch name {
+		case "type-enum":
+			cfg.applyTypeEnum(rule)
+		case "scope-enum":
+			cfg.AllowedScopes = nil
+			if rule.level > 0 && rule.always {
+				cfg.AllowedScopes, _ = rule.strings()
+			}
+		case "subject-case":
+			cfg.Style.LowercaseSubject = rule.level > 0 && requiresLowercase(rule)
+			cfg.enforce(name, rule.level == 2 && cfg.Style.LowercaseSubject)
+		case "header-max-length":
+			n, _ := rule.value.(int)
+			cfg.Style.HeaderMaxLength = 0
+			if rule.level > 0 && rule.always {
+				cfg.Style.HeaderMaxLength = n
+			}
+			cfg.enforce(name, rule.level == 2 && cfg.Style.HeaderMaxLength > 0)
+		}
+	}
+	return unsupported, nil
+}
+
+// applyTypeEnum sets the types to the ones allowed by the type-enum rule, keeping
+// the descriptions and sections of the types already configured.
+func (cfg *config) applyTypeEnum(rule commitlintRule) {
+	names, _ := rule.strings()
+	if rule.level == 0 || !rule.always || len(names) == 0 {
+		return
+	}
+
+	types := make([]ccType, len(names))
+	for i, name := range names {
+		types[i] = ccType{Name: name, Description: conventionalDescriptions[name]}
+		for _, t := range cfg.Types {
+			if t.Name == name {
+				types[i] = t
+			}
+		}
+	}
+	cfg.Types = types
+}
+
+// requiresLowercase reports whether the subject-case rule forbids subjects that
+// start with a capital letter, as config-conventional does by disallowing
+// sentence-case.
+func requiresLowercase(rule commitlintRule) bool {
+	values, _ := rule.strings()
+	for _, c := range values {
+		if rule.always && c == "lower-case" || !rule.always && c == "sentence-case" {
+			return true
+		}
+	}
+	return false
+}
+
+// enforce sets whether lint reports breaking the style rule as an error.
+func (cfg *config) enforce(rule string, on bool) {
+	if cfg.enforced == nil {
+		cfg.enforced = map[string]bool{}
+	}
+	cfg.enforced[rule] = on
+}
+
+// loadCommitlint applies the first commitlint configuration found at the root of
+// the repository, reporting the shared configurations it extends that cc does not
+// know the rules of.
+func (c *CLI) loadCommitlint(cfg *config, root string) {
+	c.reportCommitlint(c.applyCommitlintFile(cfg, root))
+}
+
+// applyCommitlintFile applies the first commitlint configuration found at the root
+// of the repository, returning the problems to report about it.
+func (c *CLI) applyCommitlintFile(cfg *config, root string) string {
+	for _, name := range commitlintFiles {
+		path := filepath.Join(root, name)
+		b, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		cl, err := parseCommitlint(name, b)
+		if err != nil {
+			return fmt.Sprintf("Error loading %s, only JSON, YAML and JavaScript object literals made of literals are supported: %s\n", path, err)
+		}
+		if cl == nil {
+			continue
+		}
+
+		unsupported, err := cfg.applyCommitlint(cl)
+		if err != nil {
+			return fmt.Sprintf("Error loading %s, its rules were not applied: %s\n", path, err)
+		}
+		if len(unsupported) > 0 {
+			return fmt.Sprintf("Ignoring extends %s in %s, only %s is supported\n", strings.Join(unsupported, ", "), name, configConventional)
+		}
+		return ""
+	}
+	return ""
+}
+
+// reportCommitlint writes the problems with the commitlint configuration, unless
+// they were written by an earlier run. The problems written last are remembered
+// in the git directory until the configuration loads without any, so that every
+// command does not repeat them.
+func (c *CLI) reportCommitlint(problems string) {
+	path, err := c.ce.output("rev-parse", "--git-path", "cc/commitlint")
+	if err != nil {
+		fmt.Fprint(c.Out, problems)
+		return
+	}
+
+	if problems == "" {
+		os.Remove(path)
+		return
+	}
+	if b, err := os.ReadFile(path); err == nil && string(b) == problems {
+		return
+	}
+
+	fmt.Fprint(c.Out, problems)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	os.WriteFile(path, []byte(problems), 0644)
+}
+
+// isScope reports whether name is one of the allowed scopes, when they are
+// restricted. No scope is always allowed.
+func (cfg *config) isScope(name string) bool {
+	if name == "" || len(cfg.AllowedScopes) == 0 {
+		return true
+	}
+	for _, s := range cfg.AllowedScopes {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,262 @@
+package cc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// commitlintRules are the same rules written in each of the formats commitlint reads.
+var commitlintRules = map[string]string{
+	".commitlintrc.json": `{
+  "extends": ["@commitlint/config-conventional"],
+  "rules": {"scope-enum": [2, "always", ["ssh", "aws"]], "header-max-length": [2, "always", 72]}
+}`,
+	".commitlintrc.yaml": `extends: "@commitlint/config-conventional"
+rules:
+  scope-enum: [2, always, [ssh, aws]]
+  header-max-length: [2, always, 72]
+`,
+	"package.json": `{
+  "name": "z",
+  "commitlint": {
+    "extends": ["@commitlint/config-conventional"],
+    "rules": {"scope-enum": [2, "always", ["ssh", "aws"]], "header-max-length": [2, "always", 72]}
+  }
+}`,
+	"commitlint.config.js": `// the shared config
+module.exports = {
+  extends: ['@commitlint/config-conventional'],
+  /* allowed scopes */
+  rules: {
+    'scope-enum': [2, 'always', ['ssh', 'aws']],
+    'header-max-length': [2, 'always', 72],
+  },
+};
+`,
+}
+
+// commitlintOutputs returns the git outputs of a repository with the files at its
+// root.
+func commitlintOutputs(t *testing.T, files map[string]string) map[string]string {
+	return map[string]string{"rev-parse --show-toplevel": writeFiles(t, files)}
+}
+
+func TestLoadCommitlint(t *testing.T) {
+	for name, content := range commitlintRules {
+		t.Run(name, func(t *testing.T) {
+			_, cli, _ := mockGitCLI(commitlintOutputs(t, map[string]string{name: content}))
+			cli.loadConfig()
+			cfg := cli.cfg
+
+			want := []string{"build", "chore", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test"}
+			if got := cfg.typeNames(); !reflect.DeepEqual(got, want) {
+				t.Errorf("got types %v want %v", got, want)
+			}
+			if got := cfg.Types[5]; got.Description != "A bug fix" {
+				t.Errorf("got %+v want the description of the type", got)
+			}
+			if want := []string{"ssh", "aws"}; !reflect.DeepEqual(cfg.AllowedScopes, want) {
+				t.Errorf("got scopes %v want %v", cfg.AllowedScopes, want)
+			}
+			if cfg.Style.HeaderMaxLength != 72 || !cfg.Style.LowercaseSubject {
+				t.Errorf("got style %+v", cfg.Style)
+			}
+			if !cfg.enforced["header-max-length"] || !cfg.enforced["subject-case"] {
+				t.Errorf("got enforced rules %v", cfg.enforced)
+			}
+		})
+	}
+
+	t.Run("Disabled and warning rules", func(t *testing.T) {
+		_, cli, _ := mockGitCLI(commitlintOutputs(t, map[string]string{
+			".commitlintrc.yml": "rules:\n  subject-case: [0]\n  header-max-length: [1, always, 50]\n  type-enum: [2, always, [feat, fix]]\n",
+		}))
+		cli.loadConfig()
+		cfg := cli.cfg
+
+		if cfg.Style.LowercaseSubject || cfg.Style.HeaderMaxLength != 50 || cfg.enforced["header-max-length"] {
+			t.Errorf("got style %+v and enforced rules %v", cfg.Style, cfg.enforced)
+		}
+		if got := cfg.Types; !reflect.DeepEqual(got, []ccType{defaultTypes[3], defaultTypes[4]}) {
+			t.Errorf("got types %v want the configured descriptions kept", got)
+		}
+	})
+
+	t.Run("Comment markers inside strings", func(t *testing.T) {
+		_, cli, _ := mockGitCLI(commitlintOutputs(t, map[string]string{
+			"commitlint.config.js": "module.exports = {\n  helpUrl: 'https://example.com/docs', // see /* */\n  rules: {'scope-enum': [2, 'always', ['ssh']]},\n};\n",
+		}))
+		cli.loadConfig()
+
+		if want := []string{"ssh"}; !reflect.DeepEqual(cli.cfg.AllowedScopes, want) {
+			t.Errorf("got %v want %v", cli.cfg.AllowedScopes, want)
+		}
+	})
+
+	t.Run("Invalid rules not applied", func(t *testing.T) {
+		testCases := map[string]string{
+			"Spread":          "module.exports = {\n  ...require('./base'),\n  rules: {'scope-enum': [2, 'always', ['ssh']]},\n};\n",
+			"Severity name":   "module.exports = { rules: {'scope-enum': ['error', 'always', ['ssh']]} };\n",
+			"Scopes not list": "module.exports = { rules: {'scope-enum': [2, 'always', {scopes: ['ssh']}]} };\n",
+			"Applicable typo": "module.exports = { rules: {'scope-enum': [2, 'allways', ['ssh']]} };\n",
+			"Length text":     "module.exports = { rules: {'header-max-length': [2, 'always', '72']} };\n",
+		}
+		for desc, content := range testCases {
+			t.Run(desc, func(t *testing.T) {
+				buffer, cli, ce := mockGitCLI(commitlintOutputs(t, map[string]string{"commitlint.config.js": content}))
+				path := filepath.Join(ce.outputs["rev-parse --show-toplevel"], "commitlint.config.js")
+				cli.loadConfig()
+
+				if !strings.HasPrefix(buffer.String(), "Error loading "+path) {
+					t.Errorf("got %q want the config reported", buffer.String())
+				}
+				if cli.cfg.AllowedScopes != nil || cli.cfg.Style != defaultStyle {
+					t.Errorf("got scopes %v and style %+v want nothing applied", cli.cfg.AllowedScopes, cli.cfg.Style)
+				}
+			})
+		}
+	})
+
+	t.Run("Errors reported once", func(t *testing.T) {
+		buffer, cli, ce := mockGitCLI(commitlintOutputs(t, map[string]string{"commitlint.config.js": "module.exports = require('./rules')\n"}))
+		root := ce.outputs["rev-parse --show-toplevel"]
+		path := filepath.Join(root, "commitlint.config.js")
+		ce.outputs["rev-parse --git-path cc/commitlint"] = filepath.Join(root, ".git", "cc", "commitlint")
+
+		for i, want := range []bool{true, false} {
+			buffer.Reset()
+			cli.loadConfig()
+			if got := strings.HasPrefix(buffer.String(), "Error loading "+path); got != want {
+				t.Errorf("got %q on load %d", buffer.String(), i+1)
+			}
+		}
+
+		os.WriteFile(path, []byte("module.exports = {};\n"), 0644)
+		cli.loadConfig()
+		os.WriteFile(path, []byte("module.exports = require('./rules')\n"), 0644)
+		buffer.Reset()
+		cli.loadConfig()
+
+		if !strings.HasPrefix(buffer.String(), "Error loading "+path) {
+			t.Errorf("got %q want the error reported again once fixed and broken", buffer.String())
+		}
+	})
+
+	t.Run(".cc.yaml takes precedence", func(t *testing.T) {
+		_, cli, _ := mockGitCLI(commitlintOutputs(t, map[string]string{
+			".commitlintrc.json": commitlintRules[".commitlintrc.json"],
+			repoConfigFile:       "allowedScopes: [cc]\n",
+		}))
+		cli.loadConfig()
+
+		if want := []string{"cc"}; !reflect.DeepEqual(cli.cfg.AllowedScopes, want) {
+			t.Errorf("got %v want %v", cli.cfg.AllowedScopes, want)
+		}
+	})
+
+	t.Run("Unsupported files reported", func(t *testing.T) {
+		buffer, cli, ce := mockGitCLI(commitlintOutputs(t, map[string]string{
+			"commitlint.config.js": "module.exports = require('./rules')\n",
+			".commitlintrc":        "extends: ['@commitlint/config-angular']\n",
+		}))
+		root := ce.outputs["rev-parse --show-toplevel"]
+		cli.loadConfig()
+
+		if want := "Ignoring extends @commitlint/config-angular in .commitlintrc"; !strings.Contains(buffer.String(), want) {
+			t.Errorf("got %q want %q", buffer.String(), want)
+		}
+		if !reflect.DeepEqual(cli.cfg.Types, defaultTypes) {
+			t.Errorf("got types %v want the defaults", cli.cfg.Types)
+		}
+
+		os.Remove(filepath.Join(root, ".commitlintrc"))
+		buffer.Reset()
+		cli.loadConfig()
+
+		if !strings.HasPrefix(buffer.String(), "Error loading "+filepath.Join(root, "commitlint.config.js")) {
+			t.Errorf("got %q want the JavaScript config reported", buffer.String())
+		}
+	})
+}
+
+func TestCommitlintLint(t *testing.T) {
+	_, cli, _ := mockGitCLI(commitlintOutputs(t, map[string]string{".commitlintrc.json": commitlintRules[".commitlintrc.json"]}))
+	cli.loadConfig()
+	cfg := cli.cfg
+
+	testCases := []struct {
+		message string
+		rules   []string
+	}{
+		{message: "perf(ssh): reuse connections", rules: nil},
+		{message: "feat(web): add pages", rules: []string{"scope-enum"}},
+		{message: "feat: Add pages", rules: []string{"subject-case"}},
+		{message: "feat(aws): " + strings.Repeat("a", 70), rules: []string{"header-max-length"}},
+		{message: "feature: add pages", rules: []string{"type-enum"}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.message, func(t *testing.T) {
+			var rules []string
+			for _, err := range cfg.lint(tC.message) {
+				rules = append(rules, err.Rule)
+			}
+			if !reflect.DeepEqual(rules, tC.rules) {
+				t.Errorf("got %v want %v", rules, tC.rules)
+			}
+		})
+	}
+
+	if errs := defaultConfig().lint("feat: Add pages"); len(errs) != 0 {
+		t.Errorf("got %v want style rules left to the prompts without commitlint", errs)
+	}
+}
+
+func TestAllowedScopes(t *testing.T) {
+	t.Run("Numbered choices", func(t *testing.T) {
+		buffer, cli, _ := mockGitCLI(map[string]string{"diff --cached --name-only": "aws/aws.go\nweb/index.html"}, "web", "1")
+		cli.cfg.AllowedScopes = []string{"ssh", "aws"}
+
+		cli.readScope()
+
+		if cli.cc.Scope != "ssh" {
+			t.Errorf("got %q want %q", cli.cc.Scope, "ssh")
+		}
+		want := "0.  \033[36;1maws\033[0m\n1.  \033[36;1mssh\033[0m\n\nEnter a scope or a number between 0 and 1: " +
+			"Scope must be one of: ssh, aws\n\nEnter a scope or a number between 0 and 1: "
+		if got := buffer.String(); got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("Picker", func(t *testing.T) {
+		var shown []option
+		_, cli, _ := mockCLI()
+		cli.cfg.AllowedScopes = []string{"ssh", "aws"}
+		cli.pick = fakePicker("-", &shown)
+		cli.cc.Scope = "aws"
+
+		cli.readScope()
+
+		want := []option{{"ssh", "allowed"}, {"aws", "allowed"}, {"-", "no scope"}}
+		if !reflect.DeepEqual(shown, want) {
+			t.Errorf("got %v want %v", shown, want)
+		}
+		if cli.cc.Scope != "" {
+			t.Errorf("got %q want no scope", cli.cc.Scope)
+		}
+	})
+
+	t.Run("Parameter", func(t *testing.T) {
+		buffer, cli, _ := mockCLI()
+		cli.cfg.AllowedScopes = []string{"ssh", "aws"}
+
+		cli.parseParams([]string{"scope", "web"})
+
+		if cli.params["scope"] || buffer.String() != "Unsupported scope: web\n" {
+			t.Errorf("got %q want the scope prompted for", buffer.String())
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package cc
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// component is a separately versioned part of a monorepo, such as a Go module in a
+// subdirectory. Its commits are the ones with one of its scopes or that touch files
+// under its path, and its versions are tagged with its tag prefix, such as ssh/v1.2.0.
+type component struct {
+	Name      string   `yaml:"name"`
+	Path      string   `yaml:"path"`
+	TagPrefix string   `yaml:"tagPrefix"`
+	Scopes    []string `yaml:"scopes"`
+}
+
+// findComponent returns the configured component with the name, with its path
+// defaulting to the name, its tag prefix to the path followed by a slash and its
+// scopes to the name. No name means no component, which is the whole repository.
+func (cfg *config) findComponent(name string) (*component, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	var names []string
+	for _, comp := range cfg.Components {
+		names = append(names, comp.Name)
+		if comp.Name != name {
+			continue
+		}
+
+		if comp.Path == "" {
+			comp.Path = comp.Name
+		}
+		if comp.TagPrefix == "" {
+			comp.TagPrefix = strings.TrimSuffix(comp.Path, "/") + "/"
+		}
+		if len(comp.Scopes) == 0 {
+			comp.Scopes = []string{comp.Name}
+		}
+		return &comp, nil
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("unknown component: %s, no components are configured", name)
+	}
+	return nil, fmt.Errorf("unknown component: %s, must be one of: %s", name, strings.Join(names, ", "))
+}
+
+// prefix returns the prefix of the tags of the component, which is empty for the
+// whole repository.
+func (comp *component) prefix() string {
+	if comp == nil {
+		return ""
+	}
+	return comp.TagPrefix
+}
+
+// version returns the version a tag of the component names, without its prefix.
+func (comp *component) version(tag string) string {
+	return strings.TrimPrefix(tag, comp.prefix())
+}
+
+// hasScope reports whether scope is one of the scopes of the component.
+func (comp *component) hasScope(scope string) bool {
+	for _, s := range comp.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// readComponentCommits returns the commits in the revision range that belong to the
+// component, newest first, or every commit in the range when there is no component.
+// Commits reverted within the range are left out along with their reverts.
+func (c *CLI) readComponentCommits(comp *component, revs string) ([]commit, error) {
+	commits, err := c.readCommits(revs)
+	if err != nil {
+		return nil, err
+	}
+	commits = cancelReverts(commits)
+	if comp == nil {
+		return commits, nil
+	}
+
+	out, err := c.ce.output("log", "--format=%H", revs, "--", comp.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	touched := map[string]bool{}
+	for _, sha := range strings.Fields(out) {
+		touched[sha] = true
+	}
+
+	var selected []commit
+	for _, cm := range commits {
+		if touched[cm.sha] || comp.hasScope(cm.cc.Scope) {
+			selected = append(selected, cm)
+		}
+	}
+	return selected, nil
+}
+
+// changelogPath returns the changelog the component's releases are written to,
+// at its path in the repository, or the CHANGELOG.md in the current directory
+// when there is no component.
+func (c *CLI) changelogPath(comp *component) string {
+	if comp == nil {
+		return changelogFile
+	}
+
+	path := filepath.Join(comp.Path, changelogFile)
+	if root, err := c.ce.output("rev-parse", "--show-toplevel"); err == nil {
+		path = filepath.Join(root, path)
+	}
+	return path
+}
@@ -0,0 +1,94 @@
+package cc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// componentLog is the git log output of the commits since ssh/v1.2.0, of which the
+// feature is scoped to ssh and the fix touches files under ssh.
+const componentLog = "aaa\x1ffeat(ssh): add agent forwarding\n\x1e" +
+	"bbb\x1ffeat(aws): add profiles\n\x1e" +
+	"ccc\x1ffix: close connections\n\x1e"
+
+// componentOutputs are the git outputs of the commits since ssh/v1.2.0, the latest
+// tag of the ssh component, of which ccc touches files under ssh.
+var componentOutputs = map[string]string{
+	"tag --list --merged HEAD":                   "v0.9.0\nssh/v1.2.0\naws-v0.3.0",
+	"tag --list --merged HEAD^":                  "v0.9.0\nssh/v1.2.0\naws-v0.3.0",
+	"log --format=%H%x1f%B%x1e ssh/v1.2.0..HEAD": componentLog,
+	"log --format=%H ssh/v1.2.0..HEAD -- ssh":    "ccc",
+	"log -1 --format=%cs HEAD":                   "2026-10-18",
+}
+
+func TestFindComponent(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Components = []component{{Name: "ssh"}, {Name: "cli", Path: "cmd/z", Scopes: []string{"cmd"}}}
+
+	got, err := cfg.findComponent("cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Path != "cmd/z" || got.TagPrefix != "cmd/z/" || got.Scopes[0] != "cmd" {
+		t.Errorf("got %+v", got)
+	}
+
+	got, _ = cfg.findComponent("ssh")
+	if got.Path != "ssh" || got.TagPrefix != "ssh/" || got.Scopes[0] != "ssh" {
+		t.Errorf("got %+v want the defaults", got)
+	}
+
+	if got, err := cfg.findComponent(""); got != nil || err != nil {
+		t.Errorf("got %+v, %v want no component", got, err)
+	}
+
+	if _, err := cfg.findComponent("web"); err == nil || !strings.Contains(err.Error(), "ssh, cli") {
+		t.Errorf("got %v want the configured components listed", err)
+	}
+}
+
+func TestComponentBump(t *testing.T) {
+	buffer, cli, ce := mockGitCLI(componentOutputs)
+	cli.cfg.Components = []component{{Name: "ssh"}, {Name: "aws", TagPrefix: "aws-"}}
+
+	if err := cli.bump(bumpArgs{component: "ssh", tag: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := buffer.String(), "ssh/v1.3.0\n"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+	if ce.command != "tag" {
+		t.Errorf("tag not created")
+	}
+}
+
+func TestComponentChangelog(t *testing.T) {
+	root := writeFiles(t, map[string]string{"ssh/ssh.go": "package ssh\n"})
+	buffer, cli, ce := mockGitCLI(componentOutputs)
+	ce.outputs["rev-parse --show-toplevel"] = root
+	cli.cfg.Components = []component{{Name: "ssh"}, {Name: "aws", TagPrefix: "aws-"}}
+
+	if err := cli.changelog(changelogArgs{to: "HEAD", version: "v1.3.0", component: "ssh", write: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(root, "ssh", changelogFile)
+	if got, want := buffer.String(), "Added v1.3.0 to "+path+"\n"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# Changelog\n\n## 1.3.0 (2026-10-18)\n\n\n" +
+		"### Features\n\n* **ssh:** add agent forwarding (aaa)\n\n\n" +
+		"### Bug Fixes\n\n* close connections (ccc)\n"
+	if got := string(b); got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
@@ -0,0 +1,145 @@
+package cc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	Z "github.com/rwxrob/bonzai/z"
+	"gopkg.in/yaml.v3"
+)
+
+// repoConfigFile is the name of the file at the root of a repository that
+// overrides the cc section of conf for that repository.
+const repoConfigFile = ".cc.yaml"
+
+// config holds the settings of cc. Defaults are overridden by the cc section
+// of conf, then by the commitlint configuration of the current repository, and
+// finally by the .cc.yaml file at its root.
+type config struct {
+	Types    []ccType     `yaml:"types"`
+	Scopes   []scopeRule  `yaml:"scopes"`
+	Check    checkConfig  `yaml:"check"`
+	Branches []branchRule `yaml:"branches"`
+	Style    styleConfig  `yaml:"style"`
+	// Coauthors is the roster of co-authors, written as Name <email>, offered
+	// before the authors of recent commits
+	Coauthors []string `yaml:"coauthors"`
+	// Components are the separately versioned parts of a monorepo
+	Components []component `yaml:"components"`
+	// Template is the name of a preset or the text of the template messages are
+	// rendered with
+	Template string `yaml:"template"`
+	tmpl     *template.Template
+	// AllowedScopes are the only scopes that can be used, when there are any
+	AllowedScopes []string `yaml:"allowedScopes"`
+	// enforced are the style rules lint reports as errors, set by commitlint
+	enforced map[string]bool
+}
+
+// defaultConfig returns the config used when nothing is configured.
+func defaultConfig() *config {
+	return &config{
+		Types:    defaultTypes,
+		Check:    checkConfig{SkipMerges: true, SkipFixups: true, SkipReverts: true},
+		Branches: defaultBranches,
+		Style:    defaultStyle,
+	}
+}
+
+// loadConfig loads the config for the CLI from conf, from the commitlint
+// configuration and from the .cc.yaml file at the root of the current
+// repository, if they exist.
+func (c *CLI) loadConfig() {
+	cfg := defaultConfig()
+
+	if Z.Conf != nil {
+		v, err := Z.Conf.Query(".cc")
+		if err == nil && strings.TrimSpace(v) != "null" {
+			if err := yaml.Unmarshal([]byte(v), cfg); err != nil {
+				fmt.Fprintf(c.Out, "Error loading cc conf: %s\n", err)
+			}
+		}
+	}
+
+	if root, err := c.ce.output("rev-parse", "--show-toplevel"); err == nil {
+		c.loadCommitlint(cfg, root)
+
+		path := filepath.Join(root, repoConfigFile)
+		if b, err := os.ReadFile(path); err == nil {
+			if err := yaml.Unmarshal(b, cfg); err != nil {
+				fmt.Fprintf(c.Out, "Error loading %s: %s\n", path, err)
+			}
+		}
+	}
+
+	if len(cfg.Types) == 0 {
+		cfg.Types = defaultTypes
+	}
+
+	tmpl, err := cfg.parseTemplate()
+	if err != nil {
+		fmt.Fprintf(c.Out, "Error in cc template, using the conventional commit format: %s\n", err)
+	}
+	cfg.tmpl = tmpl
+
+	c.cfg = cfg
+}
+
+// typesPrompt combines all conventional commit types options into one prompt.
+//
+// Each type is written in the format of: `<number>. <type> <description>`. With the type being
+// wrapped in cyan foreground color escape codes.
+func (cfg *config) typesPrompt() string {
+	width := 11
+	for _, t := range cfg.Types {
+		if len(t.Name)+3 > width {
+			width = len(t.Name) + 3
+		}
+	}
+
+	typesString := ""
+	for i, t := range cfg.Types {
+		number := fmt.Sprintf("%-4s", strconv.Itoa(i)+".")
+		padding := strings.Repeat(" ", width-len(t.Name))
+		typesString += number + "\033[36;1m" + t.Name + "\033[0m:" + padding + t.Description + "\n"
+	}
+	typesString += "\n" + cfg.numberPrompt()
+	return typesString
+}
+
+// numberPrompt asks for the number of a type.
+func (cfg *config) numberPrompt() string {
+	return fmt.Sprintf("Enter a number between 0 and %d: ", len(cfg.Types)-1)
+}
+
+// typeByNumber returns the name of the type numbered by input in the prompt.
+func (cfg *config) typeByNumber(input string) (string, bool) {
+	n, err := strconv.Atoi(input)
+	if err != nil || n < 0 || n >= len(cfg.Types) {
+		return "", false
+	}
+	return cfg.Types[n].Name, true
+}
+
+// isType reports whether name is one of the configured conventional commit types.
+func (cfg *config) isType(name string) bool {
+	for _, t := range cfg.Types {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// typeNames returns the names of the configured conventional commit types in order.
+func (cfg *config) typeNames() []string {
+	names := make([]string, len(cfg.Types))
+	for i, t := range cfg.Types {
+		names[i] = t.Name
+	}
+	return names
+}
@@ -0,0 +1,92 @@
+package cc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("Types loaded from repository config", func(t *testing.T) {
+		root := t.TempDir()
+		yaml := "types:\n  - name: feat\n    description: A new feature\n  - name: perf\n    description: Faster code\n"
+		if err := os.WriteFile(filepath.Join(root, repoConfigFile), []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, cli, _ := mockGitCLI(map[string]string{"rev-parse --show-toplevel": root})
+
+		cli.loadConfig()
+
+		got := cli.cfg.Types
+		want := []ccType{{Name: "feat", Description: "A new feature"}, {Name: "perf", Description: "Faster code"}}
+
+		if len(got) != len(want) {
+			t.Fatalf("got %v want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("got %v want %v", got[i], want[i])
+			}
+		}
+	})
+
+	t.Run("Default types used without config", func(t *testing.T) {
+		_, cli, _ := mockCLI()
+
+		cli.loadConfig()
+
+		if len(cli.cfg.Types) != len(defaultTypes) {
+			t.Errorf("got %v want %v", cli.cfg.Types, defaultTypes)
+		}
+	})
+
+	t.Run("Prompt and retries generated from types", func(t *testing.T) {
+		buffer, cli, _ := mockCLI("", "", "2")
+		cli.cfg = &config{Types: []ccType{{Name: "feat", Description: "A new feature"}, {Name: "perf", Description: "Faster code"}, {Name: "revert", Description: "Reverts a commit"}}}
+
+		cli.writeTypesPrompt()
+		cli.readType()
+
+		got := buffer.String()
+		want := "0.  \033[36;1mfeat\033[0m:       A new feature\n" +
+			"1.  \033[36;1mperf\033[0m:       Faster code\n" +
+			"2.  \033[36;1mrevert\033[0m:     Reverts a commit\n" +
+			"\nEnter a number between 0 and 2: " +
+			"Enter a valid number between 0 and 2: Enter a valid number between 0 and 2: "
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+
+		if cli.cc.Type != "revert" {
+			t.Errorf("got %q want %q", cli.cc.Type, "revert")
+		}
+	})
+
+	t.Run("Long type names widen the prompt", func(t *testing.T) {
+		cfg := &config{Types: []ccType{{Name: "dependencies", Description: "Dependency updates"}, {Name: "fix", Description: "A bug fix"}}}
+
+		got := cfg.typesPrompt()
+		want := "0.  \033[36;1mdependencies\033[0m:   Dependency updates\n" +
+			"1.  \033[36;1mfix\033[0m:            A bug fix\n" +
+			"\nEnter a number between 0 and 1: "
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("Lint uses configured types", func(t *testing.T) {
+		cfg := &config{Types: []ccType{{Name: "perf", Description: "Faster code"}}}
+
+		if errs := cfg.lint("perf: cache parsed config"); len(errs) != 0 {
+			t.Errorf("got errors %v", errs)
+		}
+
+		errs := cfg.lint("feat: add config")
+		if len(errs) != 1 || errs[0].Rule != "type-enum" {
+			t.Errorf("got %v want a type-enum error", errs)
+		}
+	})
+}
@@ -0,0 +1,294 @@
+package cc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// draftIDLayout is the layout of the time a draft is saved at that names it. Drafts
+// saved within the same second are told apart by a numbered suffix.
+const draftIDLayout = "20060102-150405"
+
+// draft is a conventional commit that was not committed, saved so that it can be
+// restored instead of typed again.
+type draft struct {
+	ID    string    `json:"-"`
+	Saved time.Time `json:"saved"`
+	CC    CC        `json:"cc"`
+}
+
+// isEmpty reports whether nothing was entered for the CC yet.
+func (cc CC) isEmpty() bool {
+	return cc.Type == "" && cc.Scope == "" && cc.Subject == "" && cc.Body == "" && len(cc.Footers) == 0
+}
+
+// draftsDir returns the directory drafts are saved in, which is inside the git
+// directory of the current repository.
+func (c *CLI) draftsDir() (string, error) {
+	dir, err := c.ce.output("rev-parse", "--git-path", "cc/drafts")
+	if err != nil {
+		fmt.Fprintln(c.Out, "Error finding drafts directory, are you inside a git repository?")
+		return "", err
+	}
+	return dir, nil
+}
+
+// saveDraft saves the CC as a draft and returns its ID. Nothing is saved when
+// nothing was entered yet.
+func (c *CLI) saveDraft() (string, error) {
+	if c.cc.isEmpty() {
+		return "", nil
+	}
+
+	dir, err := c.draftsDir()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	d := draft{Saved: time.Now(), CC: *c.cc}
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	base := d.Saved.Format(draftIDLayout)
+	for n := 1; ; n++ {
+		d.ID = base
+		if n > 1 {
+			d.ID = fmt.Sprintf("%s-%d", base, n)
+		}
+
+		f, err := os.OpenFile(filepath.Join(dir, d.ID+".json"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		_, err = f.Write(b)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return d.ID, err
+	}
+}
+
+// keepDraft saves the CC as a draft when a commit was not made, and tells the user
+// how to get it back. Messages of commits being amended or reverted are not saved.
+func (c *CLI) keepDraft() {
+	if c.amending != nil || c.reverting {
+		return
+	}
+
+	id, err := c.saveDraft()
+	if err != nil {
+		fmt.Fprintf(c.Out, "Error saving draft: %s\n", err)
+		return
+	}
+	if id != "" {
+		fmt.Fprintf(c.Out, "Saved draft %s, it will be offered the next time a commit is made\n", id)
+	}
+}
+
+// keepDraftOnInterrupt saves the CC as a draft when the user interrupts the prompts
+// with Ctrl-C, before exiting. The CC is read once the prompts wait for input, as
+// they hold mu while changing it.
+func (c *CLI) keepDraftOnInterrupt() {
+	c.mu.Lock()
+	c.interrupted = make(chan os.Signal, 1)
+	signal.Notify(c.interrupted, os.Interrupt)
+
+	go func(interrupted <-chan os.Signal) {
+		<-interrupted
+		c.mu.Lock()
+		c.interrupt()
+	}(c.interrupted)
+}
+
+// stopDraftOnInterrupt restores the default handling of Ctrl-C, so that it stops
+// git along with the CLI once the prompts are over.
+func (c *CLI) stopDraftOnInterrupt() {
+	if c.interrupted != nil {
+		signal.Stop(c.interrupted)
+	}
+}
+
+// interrupt saves the CC as a draft and exits the way a program interrupted with
+// Ctrl-C does.
+func (c *CLI) interrupt() {
+	fmt.Fprintln(c.Out)
+	c.keepDraft()
+	os.Exit(130)
+}
+
+// readDrafts returns the saved drafts, newest first.
+func (c *CLI) readDrafts() ([]draft, error) {
+	dir, err := c.draftsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var drafts []draft
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		d := draft{ID: strings.TrimSuffix(filepath.Base(path), ".json")}
+		if err := json.Unmarshal(b, &d); err != nil {
+			fmt.Fprintf(c.Out, "Skipping unreadable draft %s: %s\n", d.ID, err)
+			continue
+		}
+		drafts = append(drafts, d)
+	}
+
+	sort.SliceStable(drafts, func(i, j int) bool {
+		return drafts[i].Saved.After(drafts[j].Saved)
+	})
+	return drafts, nil
+}
+
+// findDraft returns the draft with the given ID, or the newest draft when the ID
+// is empty.
+func (c *CLI) findDraft(id string) (draft, error) {
+	drafts, err := c.readDrafts()
+	if err != nil {
+		return draft{}, err
+	}
+
+	for _, d := range drafts {
+		if id == "" || d.ID == id {
+			return d, nil
+		}
+	}
+
+	if id == "" {
+		fmt.Fprintln(c.Out, "No drafts saved")
+	} else {
+		fmt.Fprintf(c.Out, "No draft %s\n", id)
+	}
+	return draft{}, &CCError{"Draft not found"}
+}
+
+// removeDraft deletes the file of a saved draft.
+func (c *CLI) removeDraft(id string) error {
+	dir, err := c.draftsDir()
+	if err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(dir, id+".json"))
+}
+
+// offerDraft offers to restore the newest draft before prompting, so that its
+// values pre-fill the prompts. Fields passed as parameters are kept, and a restored
+// draft is removed since it is saved again if the commit is not made.
+func (c *CLI) offerDraft() {
+	if c.yes {
+		return
+	}
+
+	drafts, err := c.readDrafts()
+	if err != nil || len(drafts) == 0 {
+		return
+	}
+	d := drafts[0]
+
+	fmt.Fprintf(c.Out, "Found a draft saved on %s:\n\n\033[36;1m%s\033[0m\n\nRestore it [y/N]: ", d.Saved.Format("2006-01-02 15:04"), d.CC)
+	if input := strings.ToLower(c.readLine()); input != "y" && input != "yes" {
+		return
+	}
+
+	if !c.params["type"] {
+		c.cc.Type = d.CC.Type
+	}
+	if !c.params["scope"] {
+		c.cc.Scope = d.CC.Scope
+	}
+	if !c.params["subject"] {
+		c.cc.Subject = d.CC.Subject
+	}
+	if !c.params["body"] {
+		c.cc.Body = d.CC.Body
+	}
+	if !c.params["footer"] {
+		c.cc.Footers = d.CC.Footers
+	}
+	c.cc.Breaking = c.cc.Breaking || d.CC.Breaking
+
+	if err := c.removeDraft(d.ID); err != nil {
+		fmt.Fprintf(c.Out, "Error removing restored draft: %s\n", err)
+	}
+}
+
+// listDrafts writes the ID, the time saved and the header of every saved draft.
+func (c *CLI) listDrafts() error {
+	drafts, err := c.readDrafts()
+	if err != nil {
+		return err
+	}
+
+	for _, d := range drafts {
+		header, _, _ := strings.Cut(d.CC.String(), "\n")
+		fmt.Fprintf(c.Out, "%s  %s  \033[36;1m%s\033[0m\n", d.ID, d.Saved.Format("2006-01-02 15:04"), header)
+	}
+	return nil
+}
+
+// showDraft writes the commit message of a saved draft, the newest when id is empty.
+func (c *CLI) showDraft(id string) error {
+	d, err := c.findDraft(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.Out, d.CC)
+	return nil
+}
+
+// dropDraft deletes a saved draft, the newest when id is empty, or every draft
+// when id is all.
+func (c *CLI) dropDraft(id string) error {
+	if id == "all" {
+		drafts, err := c.readDrafts()
+		if err != nil {
+			return err
+		}
+		for _, d := range drafts {
+			if err := c.removeDraft(d.ID); err != nil {
+				fmt.Fprintf(c.Out, "Error dropping draft %s: %s\n", d.ID, err)
+				return err
+			}
+		}
+		fmt.Fprintf(c.Out, "Dropped %d drafts\n", len(drafts))
+		return nil
+	}
+
+	d, err := c.findDraft(id)
+	if err != nil {
+		return err
+	}
+
+	if err := c.removeDraft(d.ID); err != nil {
+		fmt.Fprintf(c.Out, "Error dropping draft %s: %s\n", d.ID, err)
+		return err
+	}
+	fmt.Fprintf(c.Out, "Dropped draft %s\n", d.ID)
+	return nil
+}
@@ -0,0 +1,162 @@
+package cc
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// draftsOutputs returns the git outputs of a repository saving drafts into a
+// temporary directory, along with the directory.
+func draftsOutputs(t *testing.T) (string, map[string]string) {
+	dir := filepath.Join(t.TempDir(), "cc", "drafts")
+	return dir, map[string]string{"rev-parse --git-path cc/drafts": dir}
+}
+
+func TestDrafts(t *testing.T) {
+	t.Run("Draft saved when commit not confirmed", func(t *testing.T) {
+		dir, outputs := draftsOutputs(t)
+		_, cli, _ := mockGitCLI(outputs, "n")
+		cli.cc = &CC{Type: "feat", Scope: "cc", Subject: "add drafts", Footers: []Trailer{{"Refs", ": ", "#14"}}}
+		cli.buildMessage()
+
+		if err := cli.makeCommit(); err != nil {
+			t.Fatal(err)
+		}
+
+		paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		if len(paths) != 1 {
+			t.Fatalf("got %d drafts want 1", len(paths))
+		}
+
+		d, err := cli.findDraft("")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if d.CC.String() != cli.message {
+			t.Errorf("got %q want %q", d.CC.String(), cli.message)
+		}
+	})
+
+	t.Run("Nothing saved before anything is entered", func(t *testing.T) {
+		dir, outputs := draftsOutputs(t)
+		_, cli, _ := mockGitCLI(outputs)
+
+		cli.keepDraft()
+
+		if _, err := os.Stat(dir); err == nil {
+			t.Errorf("got drafts directory want nothing saved")
+		}
+	})
+
+	t.Run("Draft restored into fields not passed as parameters", func(t *testing.T) {
+		_, outputs := draftsOutputs(t)
+		_, cli, _ := mockGitCLI(outputs, "y")
+		cli.cc = &CC{Type: "fix", Scope: "lint", Subject: "keep drafts", Body: "Saved body."}
+		if _, err := cli.saveDraft(); err != nil {
+			t.Fatal(err)
+		}
+
+		cli.cc = &CC{}
+		cli.parseParams([]string{"subject", "restore drafts"})
+		cli.offerDraft()
+
+		want := "fix(lint): restore drafts\n\nSaved body."
+		if got := cli.cc.String(); got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+
+		if drafts, _ := cli.readDrafts(); len(drafts) != 0 {
+			t.Errorf("got %d drafts want restored draft removed", len(drafts))
+		}
+	})
+
+	t.Run("Drafts listed, shown and dropped", func(t *testing.T) {
+		_, outputs := draftsOutputs(t)
+		buffer, cli, _ := mockGitCLI(outputs)
+		cli.cc = &CC{Type: "docs", Subject: "describe drafts", Body: "Body."}
+		id, err := cli.saveDraft()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cli.listDrafts()
+		if got := buffer.String(); !strings.HasPrefix(got, id+"  ") || !strings.Contains(got, "docs: describe drafts") {
+			t.Errorf("got %q want the draft listed", got)
+		}
+
+		buffer.Reset()
+		cli.showDraft(id)
+		if got := buffer.String(); got != "docs: describe drafts\n\nBody.\n" {
+			t.Errorf("got %q want the draft message", got)
+		}
+
+		if err := cli.dropDraft("all"); err != nil {
+			t.Fatal(err)
+		}
+		if err := cli.showDraft(""); err == nil {
+			t.Errorf("got no error want no drafts left")
+		}
+	})
+	t.Run("Drafts saved in a row kept apart", func(t *testing.T) {
+		_, outputs := draftsOutputs(t)
+		_, cli, _ := mockGitCLI(outputs)
+		cli.cc = &CC{Type: "feat", Subject: "first"}
+		first, err := cli.saveDraft()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cli.cc = &CC{Type: "feat", Subject: "second"}
+		second, err := cli.saveDraft()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if first == second {
+			t.Fatalf("got the same ID %s for both drafts", first)
+		}
+
+		drafts, err := cli.readDrafts()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(drafts) != 2 || drafts[0].ID != second || drafts[1].ID != first {
+			t.Errorf("got %v want both drafts, newest first", drafts)
+		}
+	})
+
+	t.Run("CC released to the interrupt handler only while prompting", func(t *testing.T) {
+		_, outputs := draftsOutputs(t)
+		_, cli, _ := mockGitCLI(outputs)
+		var released bool
+		cli.In = bufio.NewScanner(readerFunc(func(p []byte) (int, error) {
+			if released = cli.mu.TryLock(); released {
+				cli.mu.Unlock()
+			}
+			return copy(p, "feat\n"), nil
+		}))
+
+		cli.keepDraftOnInterrupt()
+		defer cli.stopDraftOnInterrupt()
+
+		if cli.mu.TryLock() {
+			t.Error("got the CC released before prompting")
+		}
+		if cli.readLine(); !released {
+			t.Error("got the CC held while prompting")
+		}
+		if cli.mu.TryLock() {
+			t.Error("got the CC released after prompting")
+		}
+	})
+}
+
+// readerFunc is an io.Reader calling the function.
+type readerFunc func(p []byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) {
+	return f(p)
+}
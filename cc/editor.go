@@ -0,0 +1,114 @@
+package cc
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// editorGuidance is appended to the message opened in the editor, in the same
+// style as the template git opens for commit messages.
+const editorGuidance = `# Please enter the body and footer of the commit message below the header.
+# Lines starting with '#' will be ignored, and the header may be edited too.
+#
+# <type>(<scope>): <subject>
+# <BLANK LINE>
+# <body>
+# <BLANK LINE>
+# <footer>
+#
+# The footer is the last paragraph, made of lines such as:
+#
+# BREAKING CHANGE: <description>
+# Closes #12
+`
+
+// editorCommand returns the editor to open, from $VISUAL or $EDITOR.
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(env); editor != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
+
+// runEditor opens path in the user's editor and waits for it to exit. The editor is
+// run through the shell so that it may contain arguments, as git does.
+func runEditor(path string) error {
+	cmd := exec.Command("sh", "-c", editorCommand()+` "$@"`, "editor", path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// editBodyAndFooter opens the message built so far in the editor, pre-filled with
+// the header and guidance comments, and sets the body and footer of the CC from
+// the edited message once the editor exits. Changes to the header are kept when it
+// still follows the conventional commit format.
+func (c *CLI) editBodyAndFooter() error {
+	f, err := os.CreateTemp("", "CC_EDITMSG-*")
+	if err != nil {
+		fmt.Fprintf(c.Out, "Error creating file to edit: %s\n", err)
+		return err
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	c.buildMessage()
+	_, err = f.WriteString(c.message + "\n\n" + editorGuidance)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(c.Out, "Error writing file to edit: %s\n", err)
+		return err
+	}
+
+	if err := c.editor(path); err != nil {
+		fmt.Fprintf(c.Out, "Error running editor %s: %s\n", editorCommand(), err)
+		return err
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(c.Out, "Error reading edited message: %s\n", err)
+		return err
+	}
+
+	message := c.cfg.untemplate(string(b))
+	edited, _ := parseMessage(message)
+	c.cc.Body = edited.Body
+	c.cc.Footers = nil
+	for _, t := range edited.Footers {
+		c.cc.Footers = append(c.cc.Footers, t.gitTrailer())
+	}
+
+	lines := cleanLines(message)
+	if len(lines) == 0 {
+		return nil
+	}
+
+	header := &CC{}
+	if headerErrs := parseHeader(header, lines[0]); len(headerErrs) > 0 {
+		fmt.Fprintln(c.Out, "Edited header ignored as it does not follow the conventional commit format:")
+		for _, err := range headerErrs {
+			fmt.Fprintf(c.Out, "  %s\n", err)
+		}
+		return nil
+	}
+
+	if !c.cfg.isType(header.Type) {
+		fmt.Fprintf(c.Out, "Unsupported type: %s\n", header.Type)
+		header.Type = c.cc.Type
+	}
+	if !c.cfg.isScope(header.Scope) {
+		fmt.Fprintf(c.Out, "Unsupported scope: %s\n", header.Scope)
+		header.Scope = c.cc.Scope
+	}
+
+	c.cc.Type = header.Type
+	c.cc.Scope = header.Scope
+	c.cc.Subject = header.Subject
+	c.cc.Breaking = header.Breaking
+	return nil
+}
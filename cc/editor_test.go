@@ -0,0 +1,92 @@
+package cc
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// fakeEditor returns an editor that checks the file it is opened on and then
+// replaces its content with edited.
+func fakeEditor(t *testing.T, edited string) func(string) error {
+	return func(path string) error {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasPrefix(string(b), "feat(cc): add edit\n\n# Please enter") {
+			t.Errorf("got %q want the header followed by guidance", string(b))
+		}
+		return os.WriteFile(path, []byte(edited), 0644)
+	}
+}
+
+func TestEditBodyAndFooter(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		edited string
+		want   string
+	}{
+		{
+			desc:   "Body of several paragraphs and multiple footers",
+			edited: "feat(cc): add edit\n\nFirst paragraph\nwrapped.\n\nSecond paragraph.\n\nCloses #12\nReviewed-by: Z\n# Please enter the body\n#\n",
+			want:   "feat(cc): add edit\n\nFirst paragraph\nwrapped.\n\nSecond paragraph.\n\nCloses: #12\nReviewed-by: Z",
+		},
+		{
+			desc:   "Edited header kept",
+			edited: "fix(lint)!: handle scissors\n\nBody.\n",
+			want:   "fix(lint)!: handle scissors\n\nBody.",
+		},
+		{
+			desc:   "Invalid header ignored",
+			edited: "handle scissors\n\nBody.\n",
+			want:   "feat(cc): add edit\n\nBody.",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			_, cli, _ := mockCLI()
+			cli.editor = fakeEditor(t, tC.edited)
+			cli.parseParams([]string{"type", "feat", "scope", "cc", "subject", "add edit", "edit"})
+
+			cli.readMissingFields()
+			cli.buildMessage()
+
+			got := cli.message
+			if got != tC.want {
+				t.Errorf("got %q want %q", got, tC.want)
+			}
+		})
+	}
+}
+
+func TestEditGitmoji(t *testing.T) {
+	buffer, cli, _ := mockCLI()
+	cli.cfg.Template = "gitmoji"
+	tmpl, err := cli.cfg.parseTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli.cfg.tmpl = tmpl
+	cli.editor = func(path string) error {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		edited := strings.Replace(string(b), "add edit", "add edit with gitmoji", 1)
+		return os.WriteFile(path, []byte(edited), 0644)
+	}
+	cli.parseParams([]string{"type", "feat", "scope", "cc", "subject", "add edit", "body", "Body.", "edit"})
+
+	cli.readMissingFields()
+	cli.buildMessage()
+
+	if want := "✨ feat(cc): add edit with gitmoji\n\nBody."; cli.message != want {
+		t.Errorf("got %q want %q", cli.message, want)
+	}
+	if strings.Contains(buffer.String(), "Edited header ignored") {
+		t.Errorf("got %q want the edited header kept", buffer.String())
+	}
+}
@@ -0,0 +1,107 @@
+package cc
+
+import (
+	"strings"
+)
+
+// breakingToken is the footer token describing a breaking change.
+const breakingToken = "BREAKING CHANGE"
+
+// knownTrailers are the footer tokens whose spelling is corrected when they are
+// entered in a different case, such as closes or co-authored-by.
+var knownTrailers = []string{
+	"Closes",
+	"Fixes",
+	"Refs",
+	"Reviewed-by",
+	"Co-authored-by",
+	"Signed-off-by",
+	breakingToken,
+	"BREAKING-CHANGE",
+}
+
+// Trailer is a footer of a conventional commit, such as `Closes #12`, written as
+// its token followed by the separator and the value. Values may span several lines.
+type Trailer struct {
+	Token     string `json:"token"`
+	Separator string `json:"separator"`
+	Value     string `json:"value"`
+}
+
+// String returns the trailer as it is written in the commit message.
+func (t Trailer) String() string {
+	return t.Token + t.Separator + t.Value
+}
+
+// gitTrailer returns the trailer in the `<token>: <value>` format that git
+// interpret-trailers understands, with the spelling of well known tokens corrected.
+// As git does not allow spaces in tokens, a BREAKING CHANGE is written with its
+// BREAKING-CHANGE synonym.
+func (t Trailer) gitTrailer() Trailer {
+	for _, known := range knownTrailers {
+		if strings.EqualFold(t.Token, known) {
+			t.Token = known
+		}
+	}
+
+	t.Token = strings.ReplaceAll(t.Token, " ", "-")
+	t.Separator = ": "
+	t.Value = strings.TrimSpace(t.Value)
+	return t
+}
+
+// parseTrailer parses a footer line written as `<token>: <value>` or as
+// `<token> #<value>`, such as `Closes #12`.
+func parseTrailer(line string) (Trailer, bool) {
+	m := trailerRegex.FindStringSubmatch(line)
+	if m == nil {
+		return Trailer{}, false
+	}
+
+	t := Trailer{Token: m[1], Separator: m[2], Value: line[len(m[0]):]}
+	if m[2] == " #" {
+		t.Separator = " "
+		t.Value = "#" + t.Value
+	}
+
+	if strings.TrimSpace(strings.TrimPrefix(t.Value, "#")) == "" {
+		return Trailer{}, false
+	}
+	return t, true
+}
+
+// parseFooters parses the lines of a footer paragraph into trailers. Indented
+// lines continue the value of the trailer above them.
+func parseFooters(paragraph []msgLine) []Trailer {
+	var footers []Trailer
+	for _, l := range paragraph {
+		if t, ok := parseTrailer(l.text); ok {
+			footers = append(footers, t)
+			continue
+		}
+		if len(footers) > 0 {
+			footers[len(footers)-1].Value += "\n" + l.text
+		}
+	}
+	return footers
+}
+
+// footer returns the footers of the conventional commit, one trailer per line.
+func (cc CC) footer() string {
+	lines := make([]string, len(cc.Footers))
+	for i, t := range cc.Footers {
+		lines[i] = t.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// breakingChange returns the description of the BREAKING CHANGE footer, or an
+// empty string when there is none.
+func (cc CC) breakingChange() string {
+	for _, t := range cc.Footers {
+		if t.Token == breakingToken || t.Token == "BREAKING-CHANGE" {
+			return t.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package cc
+
+import (
+	"testing"
+)
+
+func TestParseTrailer(t *testing.T) {
+	testCases := []struct {
+		line string
+		want Trailer
+		git  string
+		ok   bool
+	}{
+		{line: "Closes #12", want: Trailer{"Closes", " ", "#12"}, git: "Closes: #12", ok: true},
+		{line: "Refs: #7, #8", want: Trailer{"Refs", ": ", "#7, #8"}, git: "Refs: #7, #8", ok: true},
+		{line: "co-authored-by: Z <z@example.com>", want: Trailer{"co-authored-by", ": ", "Z <z@example.com>"}, git: "Co-authored-by: Z <z@example.com>", ok: true},
+		{line: "BREAKING CHANGE: params are required", want: Trailer{"BREAKING CHANGE", ": ", "params are required"}, git: "BREAKING-CHANGE: params are required", ok: true},
+		{line: "Refs: ", ok: false},
+		{line: "free text", ok: false},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.line, func(t *testing.T) {
+			got, ok := parseTrailer(tC.line)
+
+			if ok != tC.ok || got != tC.want {
+				t.Errorf("got %v %t want %v %t", got, ok, tC.want, tC.ok)
+			}
+
+			if ok && got.String() != tC.line {
+				t.Errorf("got %q want %q", got.String(), tC.line)
+			}
+
+			if ok && got.gitTrailer().String() != tC.git {
+				t.Errorf("got %q want %q", got.gitTrailer().String(), tC.git)
+			}
+		})
+	}
+}
+
+func TestParseFooters(t *testing.T) {
+	cc, _ := parseMessage("feat: add footers\n\nBREAKING CHANGE: footers are trailers\n  and are one per line\nSigned-off-by: Z <z@example.com>")
+
+	got := cc.footer()
+	want := "BREAKING CHANGE: footers are trailers\n  and are one per line\nSigned-off-by: Z <z@example.com>"
+
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	if note := cc.breakingChange(); note != "footers are trailers\n  and are one per line" {
+		t.Errorf("got %q want the BREAKING CHANGE description", note)
+	}
+}
@@ -0,0 +1,314 @@
+package cc
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// historyFormats are the formats the log and stats commands can write in.
+var historyFormats = []string{"text", "json"}
+
+// noScope names the scope of the commits without one in the stats.
+const noScope = "(none)"
+
+// historyArgs contains arguments used for the log and stats commands, which select
+// the conventional commits of a revision range with the given types and scopes.
+type historyArgs struct {
+	revs     string
+	types    []string
+	scopes   []string
+	breaking bool
+	author   string
+	since    string
+	until    string
+	format   string
+}
+
+// parseHistoryArgs parses the revision range and the parameters of the log and
+// stats commands. The type and scope parameters may be repeated to select any of
+// their values.
+func parseHistoryArgs(args []string) (historyArgs, error) {
+	hArgs := historyArgs{format: "text"}
+
+	for i := 0; i < len(args); i++ {
+		param := args[i]
+
+		switch param {
+		case "breaking":
+			hArgs.breaking = true
+			continue
+		case "type", "scope", "author", "since", "until", "format":
+		default:
+			if hArgs.revs != "" {
+				return hArgs, fmt.Errorf("unsupported parameter: %s", param)
+			}
+			hArgs.revs = param
+			continue
+		}
+
+		if i+1 >= len(args) {
+			return hArgs, fmt.Errorf("missing value for parameter: %s", param)
+		}
+		i++
+
+		switch param {
+		case "type":
+			hArgs.types = append(hArgs.types, args[i])
+		case "scope":
+			hArgs.scopes = append(hArgs.scopes, args[i])
+		case "author":
+			hArgs.author = args[i]
+		case "since":
+			hArgs.since = args[i]
+		case "until":
+			hArgs.until = args[i]
+		case "format":
+			hArgs.format = args[i]
+		}
+	}
+
+	if hArgs.revs == "" {
+		hArgs.revs = "HEAD"
+	}
+
+	for _, f := range historyFormats {
+		if hArgs.format == f {
+			return hArgs, nil
+		}
+	}
+	return hArgs, fmt.Errorf("unsupported format: %s, must be one of: %s", hArgs.format, strings.Join(historyFormats, ", "))
+}
+
+// loggedCommit is a conventional commit from the history along with its author
+// and the date it was committed.
+type loggedCommit struct {
+	commit
+	author string
+	date   string
+}
+
+// matches reports whether the commit has one of the selected types and one of the
+// selected scopes, when there are any, and is a breaking change when breaking is set.
+func (hArgs historyArgs) matches(cm commit) bool {
+	if len(hArgs.types) > 0 && !containsFold(hArgs.types, cm.cc.Type) {
+		return false
+	}
+	if len(hArgs.scopes) > 0 && !containsFold(hArgs.scopes, cm.cc.Scope) {
+		return false
+	}
+	return !hArgs.breaking || cm.isBreaking()
+}
+
+// readHistory returns the conventional commits in the revision range selected by
+// the arguments, newest first. The author and the dates are matched by git, as
+// with git log --author, --since and --until, and commits that are not
+// conventional commits are skipped.
+func (c *CLI) readHistory(hArgs historyArgs) ([]loggedCommit, error) {
+	args := []string{"log", "--format=%H%x1f%an%x1f%cs%x1f%B%x1e"}
+	if hArgs.author != "" {
+		args = append(args, "--author="+hArgs.author)
+	}
+	if hArgs.since != "" {
+		args = append(args, "--since="+hArgs.since)
+	}
+	if hArgs.until != "" {
+		args = append(args, "--until="+hArgs.until)
+	}
+
+	out, err := c.ce.output(append(args, hArgs.revs)...)
+	if err != nil {
+		fmt.Fprintf(c.Out, "Error reading commits in %s, is it a valid revision range?\n", hArgs.revs)
+		return nil, err
+	}
+
+	var commits []loggedCommit
+	for _, record := range strings.Split(out, recordSep) {
+		fields := strings.SplitN(strings.TrimSpace(record), fieldSep, 4)
+		if len(fields) < 4 {
+			continue
+		}
+
+		cc, err := Parse(c.cfg.untemplate(fields[3]))
+		if err != nil {
+			continue
+		}
+
+		cm := loggedCommit{commit: commit{sha: fields[0], cc: &cc}, author: fields[1], date: fields[2]}
+		if hArgs.matches(cm.commit) {
+			commits = append(commits, cm)
+		}
+	}
+	return commits, nil
+}
+
+// loggedEntry is a commit written by the log command as JSON.
+type loggedEntry struct {
+	SHA      string `json:"sha"`
+	Date     string `json:"date"`
+	Author   string `json:"author"`
+	Type     string `json:"type"`
+	Scope    string `json:"scope,omitempty"`
+	Breaking bool   `json:"breaking,omitempty"`
+	Header   string `json:"header"`
+}
+
+// writeLog writes a line for each commit with its short SHA, its date, its header
+// and its author, or the commits as JSON.
+func (c *CLI) writeLog(commits []loggedCommit, format string) error {
+	entries := []loggedEntry{}
+	for _, cm := range commits {
+		header, _, _ := strings.Cut(cm.cc.String(), "\n")
+		if format != "json" {
+			fmt.Fprintf(c.Out, "%s %s \033[36;1m%s\033[0m %s\n", shortSHA(cm.sha), cm.date, header, cm.author)
+			continue
+		}
+		entries = append(entries, loggedEntry{cm.sha, cm.date, cm.author, cm.cc.Type, cm.cc.Scope, cm.isBreaking(), header})
+	}
+
+	if format != "json" {
+		return nil
+	}
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(c.Out, string(b))
+	return nil
+}
+
+// statGroup counts the commits of an author or of a month by type and by scope.
+type statGroup struct {
+	Name    string         `json:"name"`
+	Commits int            `json:"commits"`
+	Types   map[string]int `json:"types"`
+	Scopes  map[string]int `json:"scopes"`
+}
+
+// statsReport summarizes the conventional commits of a range per author and per
+// month, with the groups sorted by their number of commits and by month.
+type statsReport struct {
+	Range   string      `json:"range"`
+	Commits int         `json:"commits"`
+	Authors []statGroup `json:"authors"`
+	Months  []statGroup `json:"months"`
+}
+
+// summarize counts the commits by type and by scope, per author and per month.
+func summarize(revs string, commits []loggedCommit) statsReport {
+	report := statsReport{Range: revs, Commits: len(commits), Authors: []statGroup{}, Months: []statGroup{}}
+	authors := map[string]*statGroup{}
+	months := map[string]*statGroup{}
+
+	count := func(groups map[string]*statGroup, name string, cm loggedCommit) {
+		g, ok := groups[name]
+		if !ok {
+			g = &statGroup{Name: name, Types: map[string]int{}, Scopes: map[string]int{}}
+			groups[name] = g
+		}
+
+		scope := cm.cc.Scope
+		if scope == "" {
+			scope = noScope
+		}
+		g.Commits++
+		g.Types[cm.cc.Type]++
+		g.Scopes[scope]++
+	}
+
+	for _, cm := range commits {
+		count(authors, cm.author, cm)
+		if len(cm.date) >= len("2006-01") {
+			count(months, cm.date[:len("2006-01")], cm)
+		}
+	}
+
+	for _, g := range authors {
+		report.Authors = append(report.Authors, *g)
+	}
+	sort.Slice(report.Authors, func(i, j int) bool {
+		a, b := report.Authors[i], report.Authors[j]
+		return a.Commits > b.Commits || a.Commits == b.Commits && a.Name < b.Name
+	})
+
+	for _, g := range months {
+		report.Months = append(report.Months, *g)
+	}
+	sort.Slice(report.Months, func(i, j int) bool {
+		return report.Months[i].Name < report.Months[j].Name
+	})
+	return report
+}
+
+// columns returns the types or the scopes counted in the groups, in the order of
+// the configured types followed by the others sorted by name.
+func columns(groups []statGroup, counts func(statGroup) map[string]int, order []string) []string {
+	seen := map[string]bool{}
+	for _, g := range groups {
+		for name := range counts(g) {
+			seen[name] = true
+		}
+	}
+
+	var names []string
+	for _, name := range order {
+		if seen[name] {
+			names = append(names, name)
+			delete(seen, name)
+		}
+	}
+
+	var rest []string
+	for name := range seen {
+		rest = append(rest, name)
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
+// writeStatsTable writes a table with a row for each group and a column for each
+// type or scope counted.
+func (c *CLI) writeStatsTable(title, group string, groups []statGroup, counts func(statGroup) map[string]int, order []string) {
+	names := columns(groups, counts, order)
+
+	fmt.Fprintf(c.Out, "\n\033[36;1m%s\033[0m\n\n", title)
+	w := tabwriter.NewWriter(c.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\tcommits\t%s\n", group, strings.Join(names, "\t"))
+	for _, g := range groups {
+		row := []string{g.Name, fmt.Sprint(g.Commits)}
+		for _, name := range names {
+			row = append(row, fmt.Sprint(counts(g)[name]))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+}
+
+// writeStats writes the stats as tables of the types and the scopes per author and
+// per month, or as JSON.
+func (c *CLI) writeStats(report statsReport, format string) error {
+	if format == "json" {
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.Out, string(b))
+		return nil
+	}
+
+	types := func(g statGroup) map[string]int { return g.Types }
+	scopes := func(g statGroup) map[string]int { return g.Scopes }
+
+	fmt.Fprintf(c.Out, "%d conventional commits in %s\n", report.Commits, report.Range)
+	if report.Commits == 0 {
+		return nil
+	}
+
+	c.writeStatsTable("Types per author", "author", report.Authors, types, c.cfg.typeNames())
+	c.writeStatsTable("Scopes per author", "author", report.Authors, scopes, nil)
+	c.writeStatsTable("Types per month", "month", report.Months, types, c.cfg.typeNames())
+	c.writeStatsTable("Scopes per month", "month", report.Months, scopes, nil)
+	return nil
+}
@@ -0,0 +1,145 @@
+package cc
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// historyLog is the git log output of commits by two authors over two months,
+// including one that is not a conventional commit.
+const historyLog = "aaaaaaa1\x1fJane\x1f2026-10-02\x1ffeat(ssh)!: add agent forwarding\n\x1e" +
+	"bbbbbbb2\x1fSam\x1f2026-10-01\x1ffix(aws): close sessions\n\x1e" +
+	"ccccccc3\x1fJane\x1f2026-09-30\x1fUpdate readme\n\x1e" +
+	"ddddddd4\x1fJane\x1f2026-09-29\x1fdocs: describe stats\n\x1e"
+
+// historyOutputs are the git outputs of historyLog, and of the commits of
+// historyLog since 2026-10-01 in v1..
+var historyOutputs = map[string]string{
+	"log --format=%H%x1f%an%x1f%cs%x1f%B%x1e HEAD":                    historyLog,
+	"log --format=%H%x1f%an%x1f%cs%x1f%B%x1e --since=2026-10-01 v1..": historyLog[:strings.Index(historyLog, "ccccccc3")],
+}
+
+func TestParseHistoryArgs(t *testing.T) {
+	got, err := parseHistoryArgs([]string{"v1..HEAD", "type", "feat", "type", "fix", "breaking", "author", "jane", "format", "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := historyArgs{revs: "v1..HEAD", types: []string{"feat", "fix"}, breaking: true, author: "jane", format: "json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+
+	for _, args := range [][]string{{"v1..", "v2.."}, {"scope"}, {"format", "junit"}} {
+		if _, err := parseHistoryArgs(args); err == nil {
+			t.Errorf("%v got no error", args)
+		}
+	}
+}
+
+func TestLog(t *testing.T) {
+	testCases := []struct {
+		desc string
+		args []string
+		want []string
+		text string
+	}{
+		{desc: "Every conventional commit", args: nil, want: []string{"aaaaaaa1", "bbbbbbb2", "ddddddd4"}},
+		{desc: "Types", args: []string{"type", "feat", "type", "docs"}, want: []string{"aaaaaaa1", "ddddddd4"}},
+		{
+			desc: "Scope",
+			args: []string{"scope", "aws"},
+			want: []string{"bbbbbbb2"},
+			text: "bbbbbbb 2026-10-01 \033[36;1mfix(aws): close sessions\033[0m Sam\n",
+		},
+		{desc: "Breaking", args: []string{"breaking"}, want: []string{"aaaaaaa1"}},
+		{desc: "Dates passed to git", args: []string{"v1..", "since", "2026-10-01"}, want: []string{"aaaaaaa1", "bbbbbbb2"}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			hArgs, err := parseHistoryArgs(tC.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			buffer, cli, _ := mockGitCLI(historyOutputs)
+
+			commits, err := cli.readHistory(hArgs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, cm := range commits {
+				got = append(got, cm.sha)
+			}
+			if !reflect.DeepEqual(got, tC.want) {
+				t.Errorf("got %v want %v", got, tC.want)
+			}
+
+			if tC.text == "" {
+				return
+			}
+			if err := cli.writeLog(commits, "text"); err != nil {
+				t.Fatal(err)
+			}
+			if got := buffer.String(); got != tC.text {
+				t.Errorf("got %q want %q", got, tC.text)
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	hArgs, _ := parseHistoryArgs(nil)
+	buffer, cli, _ := mockGitCLI(historyOutputs)
+	commits, _ := cli.readHistory(hArgs)
+
+	report := summarize(hArgs.revs, commits)
+
+	want := statsReport{
+		Range:   "HEAD",
+		Commits: 3,
+		Authors: []statGroup{
+			{Name: "Jane", Commits: 2, Types: map[string]int{"feat": 1, "docs": 1}, Scopes: map[string]int{"ssh": 1, noScope: 1}},
+			{Name: "Sam", Commits: 1, Types: map[string]int{"fix": 1}, Scopes: map[string]int{"aws": 1}},
+		},
+		Months: []statGroup{
+			{Name: "2026-09", Commits: 1, Types: map[string]int{"docs": 1}, Scopes: map[string]int{noScope: 1}},
+			{Name: "2026-10", Commits: 2, Types: map[string]int{"feat": 1, "fix": 1}, Scopes: map[string]int{"ssh": 1, "aws": 1}},
+		},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("got %+v want %+v", report, want)
+	}
+
+	t.Run("Table", func(t *testing.T) {
+		if err := cli.writeStats(report, "text"); err != nil {
+			t.Fatal(err)
+		}
+
+		wantTable := "\033[36;1mTypes per author\033[0m\n\n" +
+			"author  commits  docs  feat  fix\n" +
+			"Jane    2        1     1     0\n" +
+			"Sam     1        0     0     1\n"
+		if !strings.Contains(buffer.String(), wantTable) {
+			t.Errorf("got %q want it to contain %q", buffer.String(), wantTable)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		buffer, cli, _ := mockCLI()
+		if err := cli.writeStats(report, "json"); err != nil {
+			t.Fatal(err)
+		}
+
+		var got statsReport
+		if err := json.Unmarshal([]byte(buffer.String()), &got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, report) {
+			t.Errorf("got %+v want %+v", got, report)
+		}
+	})
+}
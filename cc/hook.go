@@ -0,0 +1,159 @@
+package cc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hookMarker identifies the git hooks that were written by cc.
+const hookMarker = "# installed by z cc hook"
+
+// HookError is a custom error type for errors managing git hooks.
+type HookError struct {
+	Message string
+}
+
+// Error returns the error message for the custom error type.
+func (e *HookError) Error() string {
+	return e.Message
+}
+
+// hookArgs associates each git hook that cc can install with the cc
+// subcommand and arguments the hook calls back into.
+var hookArgs = map[string]string{
+	"commit-msg":         `lint "$1"`,
+	"prepare-commit-msg": `prepare "$@"`,
+}
+
+// hookNames are the git hooks that cc can install, in the order they are listed.
+var hookNames = []string{"commit-msg", "prepare-commit-msg"}
+
+// parseHookNames returns the hooks named in args, or the commit-msg hook when
+// none are named.
+func parseHookNames(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return []string{"commit-msg"}, nil
+	}
+
+	for _, name := range args {
+		if _, ok := hookArgs[name]; !ok {
+			return nil, fmt.Errorf("unsupported hook: %s, must be one of: %s", name, strings.Join(hookNames, ", "))
+		}
+	}
+	return args, nil
+}
+
+// hookScript returns a hook that first runs any hook it replaced, which is
+// kept next to it with a .chained suffix, and then calls back into cc.
+func hookScript(name string, command []string) string {
+	quoted := make([]string, len(command))
+	for i, v := range command {
+		quoted[i] = "'" + strings.ReplaceAll(v, "'", `'\''`) + "'"
+	}
+
+	return "#!/bin/sh\n" +
+		hookMarker + "\n" +
+		"if [ -x \"$0.chained\" ]; then\n" +
+		"\t\"$0.chained\" \"$@\" || exit $?\n" +
+		"fi\n" +
+		"exec " + strings.Join(quoted, " ") + " " + hookArgs[name] + "\n"
+}
+
+// isCCHook reports whether the hook at path was written by cc.
+func isCCHook(path string) bool {
+	b, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(b), hookMarker)
+}
+
+// hooksDir returns the directory git runs hooks from for the current
+// repository, which respects core.hooksPath.
+func (c *CLI) hooksDir() (string, error) {
+	dir, err := c.ce.output("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		fmt.Fprintln(c.Out, "Error finding git hooks directory, are you inside a git repository?")
+		return "", err
+	}
+	return dir, nil
+}
+
+// installHook writes the named hook into dir so that it calls back into cc using
+// command. A hook that already exists and was not written by cc is renamed
+// with a .chained suffix and run before cc.
+func (c *CLI) installHook(dir, name string, command []string) error {
+	path := filepath.Join(dir, name)
+	chained := path + ".chained"
+
+	if _, err := os.Stat(path); err == nil && !isCCHook(path) {
+		if _, err := os.Stat(chained); err == nil {
+			fmt.Fprintf(c.Out, "Error installing %s hook: both %s and %s already exist\n", name, path, chained)
+			return &HookError{"Chained hook already exists"}
+		}
+		if err := os.Rename(path, chained); err != nil {
+			fmt.Fprintf(c.Out, "Error chaining existing %s hook: %s\n", name, err)
+			return err
+		}
+		fmt.Fprintf(c.Out, "Existing %s hook moved to %s and will be run first\n", name, chained)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Fprintf(c.Out, "Error creating hooks directory: %s\n", err)
+		return err
+	}
+
+	if err := os.WriteFile(path, []byte(hookScript(name, command)), 0755); err != nil {
+		fmt.Fprintf(c.Out, "Error writing %s hook: %s\n", name, err)
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "Installed %s hook: %s\n", name, path)
+	return nil
+}
+
+// uninstallHook removes the named hook from dir if it was written by cc and
+// restores any hook it had chained.
+func (c *CLI) uninstallHook(dir, name string) error {
+	path := filepath.Join(dir, name)
+	chained := path + ".chained"
+
+	if !isCCHook(path) {
+		fmt.Fprintf(c.Out, "No %s hook installed by cc\n", name)
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil {
+		fmt.Fprintf(c.Out, "Error removing %s hook: %s\n", name, err)
+		return err
+	}
+
+	if _, err := os.Stat(chained); err == nil {
+		if err := os.Rename(chained, path); err != nil {
+			fmt.Fprintf(c.Out, "Error restoring chained %s hook: %s\n", name, err)
+			return err
+		}
+		fmt.Fprintf(c.Out, "Restored previous %s hook: %s\n", name, path)
+	}
+
+	fmt.Fprintf(c.Out, "Uninstalled %s hook\n", name)
+	return nil
+}
+
+// hookStatus writes whether the named hook in dir is installed by cc, whether
+// it chains another hook, or whether some other hook is in its place.
+func (c *CLI) hookStatus(dir, name string) {
+	path := filepath.Join(dir, name)
+
+	switch _, err := os.Stat(path); {
+	case err != nil:
+		fmt.Fprintf(c.Out, "%s: not installed\n", name)
+	case !isCCHook(path):
+		fmt.Fprintf(c.Out, "%s: another hook is installed at %s\n", name, path)
+	default:
+		if _, err := os.Stat(path + ".chained"); err == nil {
+			fmt.Fprintf(c.Out, "%s: installed, chaining %s.chained\n", name, path)
+			return
+		}
+		fmt.Fprintf(c.Out, "%s: installed\n", name)
+	}
+}
@@ -0,0 +1,153 @@
+package cc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHook(t *testing.T) {
+	command := []string{"/usr/local/bin/z", "cc"}
+
+	t.Run("Hook installed and uninstalled", func(t *testing.T) {
+		dir := t.TempDir()
+		buffer, cli, _ := mockCLI()
+
+		if err := cli.installHook(dir, "commit-msg", command); err != nil {
+			t.Fatal(err)
+		}
+
+		b, _ := os.ReadFile(filepath.Join(dir, "commit-msg"))
+		got := string(b)
+		want := hookScript("commit-msg", command)
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+
+		buffer.Reset()
+		cli.hookStatus(dir, "commit-msg")
+
+		if buffer.String() != "commit-msg: installed\n" {
+			t.Errorf("got %q want %q", buffer.String(), "commit-msg: installed\n")
+		}
+
+		if err := cli.uninstallHook(dir, "commit-msg"); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, "commit-msg")); err == nil {
+			t.Errorf("hook not removed")
+		}
+	})
+
+	t.Run("Existing hook chained and restored", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "commit-msg")
+		existing := "#!/bin/sh\nexit 0\n"
+		if err := os.WriteFile(path, []byte(existing), 0755); err != nil {
+			t.Fatal(err)
+		}
+		buffer, cli, _ := mockCLI()
+
+		if err := cli.installHook(dir, "commit-msg", command); err != nil {
+			t.Fatal(err)
+		}
+
+		b, _ := os.ReadFile(path + ".chained")
+		if string(b) != existing {
+			t.Errorf("got %q want %q", string(b), existing)
+		}
+
+		buffer.Reset()
+		cli.hookStatus(dir, "commit-msg")
+
+		got := buffer.String()
+		want := "commit-msg: installed, chaining " + path + ".chained\n"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+
+		if err := cli.uninstallHook(dir, "commit-msg"); err != nil {
+			t.Fatal(err)
+		}
+
+		b, _ = os.ReadFile(path)
+		if string(b) != existing {
+			t.Errorf("got %q want %q", string(b), existing)
+		}
+	})
+
+	t.Run("Existing chained hook kept", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "commit-msg")
+		os.WriteFile(path, []byte("#!/bin/sh\n"), 0755)
+		os.WriteFile(path+".chained", []byte("#!/bin/sh\n"), 0755)
+		_, cli, _ := mockCLI()
+
+		err := cli.installHook(dir, "commit-msg", command)
+
+		var hookErr *HookError
+		if !errors.As(err, &hookErr) {
+			t.Errorf("got %v want a HookError", err)
+		}
+	})
+
+	t.Run("Reinstalling does not chain itself", func(t *testing.T) {
+		dir := t.TempDir()
+		_, cli, _ := mockCLI()
+
+		cli.installHook(dir, "commit-msg", command)
+		cli.installHook(dir, "commit-msg", command)
+
+		if _, err := os.Stat(filepath.Join(dir, "commit-msg.chained")); err == nil {
+			t.Errorf("cc hook chained itself")
+		}
+	})
+
+	t.Run("Other hook reported", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "commit-msg")
+		os.WriteFile(path, []byte("#!/bin/sh\n"), 0755)
+		buffer, cli, _ := mockCLI()
+
+		cli.hookStatus(dir, "commit-msg")
+		cli.uninstallHook(dir, "commit-msg")
+
+		got := buffer.String()
+		want := "commit-msg: another hook is installed at " + path + "\nNo commit-msg hook installed by cc\n"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+}
+
+func TestParseHookNames(t *testing.T) {
+	testCases := []struct {
+		desc string
+		args []string
+		want []string
+		err  bool
+	}{
+		{desc: "Default", args: nil, want: []string{"commit-msg"}},
+		{desc: "Both hooks", args: []string{"commit-msg", "prepare-commit-msg"}, want: []string{"commit-msg", "prepare-commit-msg"}},
+		{desc: "Unsupported hook", args: []string{"pre-push"}, err: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := parseHookNames(tC.args)
+
+			if (err != nil) != tC.err || strings.Join(got, " ") != strings.Join(tC.want, " ") {
+				t.Errorf("got %v %v want %v", got, err, tC.want)
+			}
+		})
+	}
+
+	if script := hookScript("prepare-commit-msg", []string{"z", "cc"}); !strings.HasSuffix(script, "exec 'z' 'cc' prepare \"$@\"\n") {
+		t.Errorf("got %q want the hook to call prepare", script)
+	}
+}
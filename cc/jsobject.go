@@ -0,0 +1,382 @@
+package cc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// jsToken is a token of a JavaScript source: a string literal, a number, an
+// identifier or a punctuator, along with the line it starts on.
+type jsToken struct {
+	kind string
+	text string
+	line int
+}
+
+const (
+	jsString = "string"
+	jsNumber = "number"
+	jsIdent  = "identifier"
+	jsPunct  = "punctuator"
+)
+
+// tokenizeJS splits a JavaScript source into tokens, leaving out whitespace and
+// comments. Comment markers inside string literals are part of the strings, and
+// string literals are decoded. A template literal with a substitution is an error,
+// as its value is only known when the configuration is run.
+func tokenizeJS(src string) ([]jsToken, error) {
+	var tokens []jsToken
+	line := 1
+
+	for i := 0; i < len(src); {
+		r, size := utf8.DecodeRuneInString(src[i:])
+
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i += size
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case r == '"' || r == '\'' || r == '`':
+			s, n, err := readJSString(src[i:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			tokens = append(tokens, jsToken{jsString, s, line})
+			line += strings.Count(src[i:i+n], "\n")
+			i += n
+		case r >= '0' && r <= '9' || r == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			n := 1
+			for n < len(src[i:]) && (isIdentByte(src[i+n]) || src[i+n] == '.') {
+				n++
+			}
+			tokens = append(tokens, jsToken{jsNumber, src[i : i+n], line})
+			i += n
+		case r == '_' || r == '$' || unicode.IsLetter(r):
+			n := size
+			for n < len(src[i:]) && isIdentByte(src[i+n]) {
+				n++
+			}
+			tokens = append(tokens, jsToken{jsIdent, src[i : i+n], line})
+			i += n
+		case strings.HasPrefix(src[i:], "..."):
+			tokens = append(tokens, jsToken{jsPunct, "...", line})
+			i += 3
+		default:
+			tokens = append(tokens, jsToken{jsPunct, string(r), line})
+			i += size
+		}
+	}
+	return tokens, nil
+}
+
+// isIdentByte reports whether b can be part of an identifier or a number.
+func isIdentByte(b byte) bool {
+	return b == '_' || b == '$' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+// readJSString decodes the string literal src starts with, returning its value and
+// its length in src.
+func readJSString(src string) (string, int, error) {
+	quote := src[0]
+	var b strings.Builder
+
+	for i := 1; i < len(src); {
+		c := src[i]
+		switch {
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c == '\n' && quote != '`':
+			return "", 0, fmt.Errorf("unterminated string")
+		case c == '$' && quote == '`' && strings.HasPrefix(src[i:], "${"):
+			return "", 0, fmt.Errorf("template literals with substitutions are not supported")
+		case c == '\\' && i+1 < len(src):
+			n, err := readJSEscape(&b, src[i+1:])
+			if err != nil {
+				return "", 0, err
+			}
+			i += 1 + n
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// readJSEscape writes the character of the escape sequence that follows a
+// backslash, returning the length of the sequence.
+func readJSEscape(b *strings.Builder, src string) (int, error) {
+	simple := map[byte]string{'n': "\n", 't': "\t", 'r': "\r", 'b': "\b", 'f': "\f", 'v': "\v", '0': "\x00", '\n': ""}
+	if s, ok := simple[src[0]]; ok {
+		b.WriteString(s)
+		return 1, nil
+	}
+
+	var digits string
+	var n int
+	switch {
+	case src[0] == 'x' && len(src) >= 3:
+		digits, n = src[1:3], 3
+	case strings.HasPrefix(src, "u{"):
+		end := strings.IndexByte(src, '}')
+		if end < 0 {
+			return 0, fmt.Errorf("invalid escape sequence")
+		}
+		digits, n = src[2:end], end+1
+	case src[0] == 'u' && len(src) >= 5:
+		digits, n = src[1:5], 5
+	default:
+		b.WriteByte(src[0])
+		return 1, nil
+	}
+
+	code, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid escape sequence \\%s", src[:n])
+	}
+	b.WriteRune(rune(code))
+	return n, nil
+}
+
+// jsParser parses the tokens of a JavaScript value made of object and array
+// literals, strings, numbers, booleans and null.
+type jsParser struct {
+	tokens []jsToken
+	pos    int
+}
+
+// peek returns the current token, or an empty token at the end of the source.
+func (p *jsParser) peek() jsToken {
+	if p.pos >= len(p.tokens) {
+		return jsToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+// is reports whether the current token is the punctuator text.
+func (p *jsParser) is(text string) bool {
+	t := p.peek()
+	return t.kind == jsPunct && t.text == text
+}
+
+// unsupported returns the error of a token the parser does not accept.
+func (p *jsParser) unsupported(t jsToken, what string) error {
+	switch {
+	case t.kind == "":
+		return fmt.Errorf("unexpected end of file, expected %s", what)
+	case t.text == "...":
+		return fmt.Errorf("line %d: spread syntax is not supported", t.line)
+	}
+	return fmt.Errorf("line %d: unsupported %q, expected %s", t.line, t.text, what)
+}
+
+// value parses a literal value.
+func (p *jsParser) value() (interface{}, error) {
+	t := p.peek()
+	switch {
+	case t.kind == jsPunct && t.text == "{":
+		return p.object()
+	case t.kind == jsPunct && t.text == "[":
+		return p.array()
+	case t.kind == jsString:
+		p.pos++
+		return t.text, nil
+	case t.kind == jsNumber || t.kind == jsPunct && t.text == "-":
+		return p.number()
+	case t.kind == jsIdent && (t.text == "true" || t.text == "false"):
+		p.pos++
+		return t.text == "true", nil
+	case t.kind == jsIdent && t.text == "null":
+		p.pos++
+		return nil, nil
+	}
+	return nil, p.unsupported(t, "a literal value")
+}
+
+// number parses a decimal number, which may be negative.
+func (p *jsParser) number() (interface{}, error) {
+	sign := ""
+	if p.is("-") {
+		sign = "-"
+		p.pos++
+	}
+
+	t := p.peek()
+	if t.kind != jsNumber {
+		return nil, p.unsupported(t, "a number")
+	}
+	f, err := strconv.ParseFloat(strings.ReplaceAll(t.text, "_", ""), 64)
+	if err != nil {
+		return nil, fmt.Errorf("line %d: unsupported number %s", t.line, t.text)
+	}
+	p.pos++
+	return json.Number(sign + strconv.FormatFloat(f, 'f', -1, 64)), nil
+}
+
+// object parses an object literal whose keys are identifiers, strings or numbers.
+func (p *jsParser) object() (interface{}, error) {
+	p.pos++
+	obj := map[string]interface{}{}
+
+	for !p.is("}") {
+		key := p.peek()
+		if key.kind != jsIdent && key.kind != jsString && key.kind != jsNumber {
+			return nil, p.unsupported(key, "a property name")
+		}
+		p.pos++
+
+		if !p.is(":") {
+			return nil, p.unsupported(p.peek(), `":" after `+key.text)
+		}
+		p.pos++
+
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		obj[key.text] = v
+
+		if !p.is(",") {
+			break
+		}
+		p.pos++
+	}
+
+	if !p.is("}") {
+		return nil, p.unsupported(p.peek(), `"," or "}"`)
+	}
+	p.pos++
+	return obj, nil
+}
+
+// array parses an array literal.
+func (p *jsParser) array() (interface{}, error) {
+	p.pos++
+	arr := []interface{}{}
+
+	for !p.is("]") {
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, v)
+
+		if !p.is(",") {
+			break
+		}
+		p.pos++
+	}
+
+	if !p.is("]") {
+		return nil, p.unsupported(p.peek(), `"," or "]"`)
+	}
+	p.pos++
+	return arr, nil
+}
+
+// exportedJSObject returns as JSON the object literal a JavaScript configuration
+// exports with module.exports = or export default, or the object literal the
+// constant it exports is declared with. The object may only contain literals, and
+// nothing may follow the export but a semicolon, so that what is returned is
+// exactly the configuration that would be loaded.
+func exportedJSObject(src string) ([]byte, error) {
+	tokens, err := tokenizeJS(src)
+	if err != nil {
+		return nil, err
+	}
+
+	start := -1
+	for i := range tokens {
+		if i+3 < len(tokens) && tokens[i].text == "module" && tokens[i+1].text == "." && tokens[i+2].text == "exports" && tokens[i+3].text == "=" {
+			start = i + 4
+		} else if i+1 < len(tokens) && tokens[i].text == "export" && tokens[i+1].text == "default" {
+			start = i + 2
+		}
+		if start >= 0 {
+			break
+		}
+	}
+	if start < 0 {
+		return nil, fmt.Errorf("no module.exports or export default")
+	}
+
+	p := &jsParser{tokens: tokens, pos: start}
+	exported := p.peek()
+	var v interface{}
+	if exported.kind == jsIdent && (start+1 == len(tokens) || tokens[start+1].text == ";") {
+		p.pos++
+		if v, err = declaredJSObject(tokens[:start], exported); err != nil {
+			return nil, err
+		}
+	} else {
+		if !p.is("{") {
+			return nil, p.unsupported(exported, "an object literal")
+		}
+		if v, err = p.value(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.is(";") {
+		p.pos++
+	}
+	if p.pos < len(tokens) {
+		return nil, fmt.Errorf("line %d: unsupported statement after the exported object", p.peek().line)
+	}
+	return json.Marshal(v)
+}
+
+// declaredJSObject returns the object literal the variable named by the exported
+// identifier is declared with in the tokens before the export. The variable may
+// not be used anywhere else, as changes made to the object would be missed.
+func declaredJSObject(tokens []jsToken, exported jsToken) (interface{}, error) {
+	var v interface{}
+	declared := false
+
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.kind != jsIdent || t.text != exported.text {
+			continue
+		}
+
+		isDeclaration := i > 0 && (tokens[i-1].text == "const" || tokens[i-1].text == "let" || tokens[i-1].text == "var") &&
+			i+1 < len(tokens) && tokens[i+1].text == "="
+		if declared || !isDeclaration {
+			return nil, fmt.Errorf("line %d: unsupported use of %s, the exported object may only be declared and exported", t.line, t.text)
+		}
+
+		p := &jsParser{tokens: tokens, pos: i + 2}
+		if !p.is("{") {
+			return nil, p.unsupported(p.peek(), "an object literal")
+		}
+		var err error
+		if v, err = p.value(); err != nil {
+			return nil, err
+		}
+		declared = true
+		i = p.pos - 1
+	}
+
+	if !declared {
+		return nil, fmt.Errorf("line %d: %s is not declared with an object literal", exported.line, exported.text)
+	}
+	return v, nil
+}
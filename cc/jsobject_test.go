@@ -0,0 +1,125 @@
+package cc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExportedJSObject(t *testing.T) {
+	testCases := []struct {
+		desc string
+		src  string
+		want string
+		err  string
+	}{
+		{
+			desc: "Comments and trailing commas",
+			src:  "// config\nmodule.exports = {\n  /* types */\n  rules: {'type-enum': [2, 'always', ['feat', 'fix',],],},\n};\n",
+			want: `{"rules":{"type-enum":[2,"always",["feat","fix"]]}}`,
+		},
+		{
+			desc: "Comment markers inside strings",
+			src:  "module.exports = {\n  helpUrl: 'https://example.com/*docs*/', // the docs\n  prompt: \"a // b\",\n}\n",
+			want: `{"helpUrl":"https://example.com/*docs*/","prompt":"a // b"}`,
+		},
+		{
+			desc: "Escapes and template literals",
+			src:  "export default { a: 'don\\'t', b: \"\\u00e9\\n\", c: `x`, d: -1.5, e: true, f: null }",
+			want: `{"a":"don't","b":"é\n","c":"x","d":-1.5,"e":true,"f":null}`,
+		},
+		{
+			desc: "Statements before the export",
+			src:  "'use strict';\nconst x = require('x');\nmodule.exports = { extends: ['@commitlint/config-conventional'] };\n",
+			want: `{"extends":["@commitlint/config-conventional"]}`,
+		},
+		{
+			desc: "Exported constant",
+			src:  "const Configuration = {\n  rules: {'scope-enum': [2, 'always', ['ssh']]},\n};\n\nexport default Configuration;\n",
+			want: `{"rules":{"scope-enum":[2,"always",["ssh"]]}}`,
+		},
+		{
+			desc: "Exported constant changed",
+			src:  "const config = { rules: {} };\nconfig.rules['scope-enum'] = [2, 'always', ['ssh']];\nmodule.exports = config;\n",
+			err:  "line 2: unsupported use of config, the exported object may only be declared and exported",
+		},
+		{
+			desc: "Exported constant not an object",
+			src:  "const config = require('./base');\nmodule.exports = config;\n",
+			err:  `line 1: unsupported "require", expected an object literal`,
+		},
+		{
+			desc: "Exported constant not declared",
+			src:  "module.exports = config;\n",
+			err:  "line 1: config is not declared with an object literal",
+		},
+		{
+			desc: "Spread",
+			src:  "module.exports = {\n  ...require('./base'),\n  rules: {},\n};\n",
+			err:  "line 2: spread syntax is not supported",
+		},
+		{
+			desc: "Function call",
+			src:  "module.exports = {\n  rules: {'scope-enum': [2, 'always', scopes()]},\n};\n",
+			err:  `line 2: unsupported "scopes", expected a literal value`,
+		},
+		{
+			desc: "Shorthand property",
+			src:  "const rules = {};\nmodule.exports = { rules };\n",
+			err:  `line 2: unsupported "}", expected ":" after rules`,
+		},
+		{
+			desc: "Template substitution",
+			src:  "module.exports = { helpUrl: `${base}/docs` };\n",
+			err:  "line 1: template literals with substitutions are not supported",
+		},
+		{
+			desc: "Exported call",
+			src:  "module.exports = require('./rules');\n",
+			err:  `line 1: unsupported "require", expected an object literal`,
+		},
+		{
+			desc: "Changed after export",
+			src:  "module.exports = { rules: {} };\nmodule.exports.rules.x = [0];\n",
+			err:  "line 2: unsupported statement after the exported object",
+		},
+		{
+			desc: "Nothing exported",
+			src:  "const config = { rules: {} };\n",
+			err:  "no module.exports or export default",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := exportedJSObject(tC.src)
+
+			if tC.err != "" {
+				if err == nil || err.Error() != tC.err {
+					t.Fatalf("got error %v want %q", err, tC.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tC.want {
+				t.Errorf("got %s want %s", got, tC.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeJS(t *testing.T) {
+	tokens, err := tokenizeJS("a /* one\ntwo */ 'b'\n// c\n1_000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, tok := range tokens {
+		got = append(got, tok.kind+" "+tok.text+" "+strings.Repeat("+", tok.line))
+	}
+	want := []string{"identifier a +", "string b ++", "number 1_000 ++++"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
@@ -0,0 +1,322 @@
+package cc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// LintError is a custom error type describing where and how a commit
+// message breaks the conventional commit format.
+type LintError struct {
+	Line    int    `json:"line"`
+	Col     int    `json:"col"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Error returns the position, message and rule for the custom error type.
+func (e *LintError) Error() string {
+	return fmt.Sprintf("%d:%d: %s [%s]", e.Line, e.Col, e.Message, e.Rule)
+}
+
+// msgLine is a line of a commit message along with its line number in the
+// original text, so that positions survive the removal of comments.
+type msgLine struct {
+	num  int
+	text string
+}
+
+// scissors marks the line after which git discards the rest of a commit message.
+const scissors = "# ------------------------ >8 ------------------------"
+
+// trailerRegex matches a git trailer or conventional commit footer line such as
+// `Refs: #12`, `Closes #12` or `BREAKING CHANGE: description`.
+var trailerRegex = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*|BREAKING CHANGE)(: | #)`)
+
+// breakingRegex matches lines that attempt to start a BREAKING CHANGE footer: the
+// token in any case followed by a colon, or in upper case without one. Prose that
+// starts with the words, such as "Breaking changes are listed", is not matched.
+var breakingRegex = regexp.MustCompile(`^(?:(?i:breaking[ -]change)\s*:|BREAKING[ -]CHANGE\b)`)
+
+// cleanLines splits a message into lines the same way git cleans up a commit
+// message: comments and everything after the scissors line are removed along
+// with trailing whitespace and leading and trailing blank lines.
+func cleanLines(message string) []msgLine {
+	var lines []msgLine
+	for i, text := range strings.Split(message, "\n") {
+		if text == scissors {
+			break
+		}
+		if strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimRight(text, " \t\r")
+		if text == "" && len(lines) == 0 {
+			continue
+		}
+		lines = append(lines, msgLine{num: i + 1, text: text})
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1].text == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// parseMessage parses a commit message into a CC, returning every way in which the
+// message breaks the conventional commit format that buildMessage produces.
+func parseMessage(message string) (*CC, []*LintError) {
+	cc := &CC{}
+	lines := cleanLines(message)
+
+	if len(lines) == 0 {
+		return cc, []*LintError{{1, 1, "header-empty", "commit message is empty"}}
+	}
+
+	errs := parseHeader(cc, lines[0])
+
+	if len(lines) > 1 && lines[1].text != "" {
+		errs = append(errs, &LintError{lines[1].num, 1, "body-leading-blank", "header must be followed by a blank line"})
+	}
+
+	paragraphs := splitParagraphs(lines[1:])
+	if len(paragraphs) == 0 {
+		return cc, errs
+	}
+
+	errs = append(errs, lintBreakingChanges(paragraphs)...)
+
+	// the body is kept as written, blank lines included, up to the footers
+	body := lines[1:]
+	if last := paragraphs[len(paragraphs)-1]; isFooter(last) {
+		cc.Footers = parseFooters(last)
+		for i, l := range body {
+			if l.num == last[0].num {
+				body = body[:i]
+				break
+			}
+		}
+	}
+	cc.Body = strings.Trim(joinLines(body), "\n")
+
+	return cc, errs
+}
+
+// parseHeader parses the `<type>(<scope>)!: <subject>` header line into cc.
+func parseHeader(cc *CC, l msgLine) []*LintError {
+	var errs []*LintError
+	text := l.text
+
+	if !strings.Contains(text, ":") {
+		return append(errs, &LintError{l.num, 1, "header-format", "header must be written as <type>(<scope>): <subject>"})
+	}
+
+	i := 0
+	for i < len(text) && isLetter(text[i]) {
+		i++
+	}
+	cc.Type = text[:i]
+
+	if cc.Type == "" {
+		return append(errs, &LintError{l.num, 1, "type-empty", "header must start with a type"})
+	}
+
+	if i < len(text) && text[i] == '(' {
+		end := strings.IndexByte(text[i:], ')')
+		if end < 0 {
+			return append(errs, &LintError{l.num, i + 1, "scope-format", "scope is missing a closing parenthesis"})
+		}
+		if strings.TrimSpace(text[i+1:i+end]) == "" {
+			errs = append(errs, &LintError{l.num, i + 2, "scope-empty", "scope must not be empty when parentheses are used"})
+		}
+		cc.Scope = text[i+1 : i+end]
+		i += end + 1
+	}
+
+	if i < len(text) && text[i] == '!' {
+		cc.Breaking = true
+		i++
+	}
+
+	if i >= len(text) || text[i] != ':' {
+		return append(errs, &LintError{l.num, i + 1, "header-format", `expected ":" after the type and scope`})
+	}
+	i++
+
+	if i >= len(text) {
+		return append(errs, &LintError{l.num, i + 2, "subject-empty", "subject must not be empty"})
+	}
+
+	if text[i] != ' ' {
+		return append(errs, &LintError{l.num, i + 1, "header-format", `expected a space after ":"`})
+	}
+	i++
+
+	cc.Subject = text[i:]
+	if strings.TrimSpace(cc.Subject) == "" {
+		errs = append(errs, &LintError{l.num, i + 1, "subject-empty", "subject must not be empty"})
+	}
+
+	return errs
+}
+
+// lintBreakingChanges checks that every BREAKING CHANGE footer starts a
+// paragraph, or is part of the footer paragraph, and is written correctly.
+func lintBreakingChanges(paragraphs [][]msgLine) []*LintError {
+	var errs []*LintError
+	for i, p := range paragraphs {
+		footer := i == len(paragraphs)-1 && isFooter(p)
+		for j, l := range p {
+			if !breakingRegex.MatchString(l.text) {
+				continue
+			}
+
+			prefix := strings.HasPrefix(l.text, "BREAKING CHANGE:") || strings.HasPrefix(l.text, "BREAKING-CHANGE:")
+			description := strings.TrimPrefix(l.text[len("BREAKING CHANGE"):], ":")
+
+			switch {
+			case j > 0 && !footer:
+				errs = append(errs, &LintError{l.num, 1, "footer-leading-blank", "BREAKING CHANGE footer must be preceded by a blank line"})
+			case prefix && strings.TrimSpace(description) == "":
+				errs = append(errs, &LintError{l.num, len("BREAKING CHANGE: ") + 1, "breaking-change-empty", "BREAKING CHANGE footer must have a description"})
+			case !prefix || description[0] != ' ':
+				errs = append(errs, &LintError{l.num, 1, "breaking-change-format", `BREAKING CHANGE footer must be written as "BREAKING CHANGE: <description>"`})
+			}
+		}
+	}
+	return errs
+}
+
+// splitParagraphs groups lines into paragraphs separated by blank lines.
+func splitParagraphs(lines []msgLine) [][]msgLine {
+	var paragraphs [][]msgLine
+	var current []msgLine
+	for _, l := range lines {
+		if l.text == "" {
+			if current != nil {
+				paragraphs = append(paragraphs, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, l)
+	}
+	if current != nil {
+		paragraphs = append(paragraphs, current)
+	}
+	return paragraphs
+}
+
+// isFooter reports whether every line of a paragraph is a footer, allowing
+// indented lines that continue the value of the footer above them.
+func isFooter(paragraph []msgLine) bool {
+	for i, l := range paragraph {
+		if trailerRegex.MatchString(l.text) {
+			continue
+		}
+		if i > 0 && (l.text[0] == ' ' || l.text[0] == '\t') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// joinLines joins the text of lines with newlines.
+func joinLines(lines []msgLine) string {
+	text := make([]string, len(lines))
+	for i, l := range lines {
+		text[i] = l.text
+	}
+	return strings.Join(text, "\n")
+}
+
+// isLetter reports whether b is an ASCII letter.
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// isAutogenerated reports whether a message was written by git itself, such as
+// merge commits and fixup commits, which are not expected to be conventional.
+func isAutogenerated(message string) bool {
+	for _, prefix := range []string{"Merge ", gitRevertPrefix, "fixup! ", "squash! ", "amend! "} {
+		if strings.HasPrefix(message, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// lint checks a commit message against the conventional commit format and the
+// configured types. Messages generated by git are not checked.
+func (cfg *config) lint(message string) []*LintError {
+	lines := cleanLines(message)
+	if len(lines) > 0 && isAutogenerated(lines[0].text) {
+		return nil
+	}
+	return cfg.lintFormat(message)
+}
+
+// lintFormat checks a commit message against the conventional commit format and the
+// configured types, whoever wrote it, along with the revert type of reverts. The
+// scope must be one of the allowed scopes when they are restricted, and the style
+// rules a commitlint configuration makes errors are checked as well. The emoji of
+// the gitmoji preset is allowed at the start of the header when it is configured.
+func (cfg *config) lintFormat(message string) []*LintError {
+	message = cfg.untemplate(message)
+	lines := cleanLines(message)
+	cc, errs := parseMessage(message)
+	if cc.Type != "" && cc.Type != revertType && !cfg.isType(cc.Type) {
+		typeErr := &LintError{lines[0].num, 1, "type-enum", fmt.Sprintf("type %q must be one of: %s", cc.Type, strings.Join(cfg.typeNames(), ", "))}
+		errs = append([]*LintError{typeErr}, errs...)
+	}
+	if !cfg.isScope(cc.Scope) {
+		col := strings.Index(lines[0].text, "(") + 2
+		errs = append(errs, &LintError{lines[0].num, col, "scope-enum", fmt.Sprintf("scope %q must be one of: %s", cc.Scope, strings.Join(cfg.AllowedScopes, ", "))})
+	}
+	if len(errs) == 0 && len(lines) > 0 {
+		for _, v := range cfg.Style.check(*cc, lines[0].text) {
+			if cfg.enforced[v.rule] {
+				errs = append(errs, &LintError{lines[0].num, 1, v.rule, v.message})
+			}
+		}
+	}
+	return errs
+}
+
+// lintMessage writes every lint error found in message and reports whether the
+// message is valid.
+func (c *CLI) lintMessage(message string) bool {
+	errs := c.cfg.lint(message)
+	if len(errs) == 0 {
+		return true
+	}
+
+	fmt.Fprintln(c.Out, "Commit message does not follow the conventional commit format:")
+	for _, err := range errs {
+		fmt.Fprintf(c.Out, "  %s\n", err)
+	}
+	return false
+}
+
+// readMessage returns the commit message to lint from the arguments. With no
+// arguments or "-" the message is read from in, a single argument naming a file
+// is read from that file and anything else is treated as the message itself.
+func readMessage(in io.Reader, args []string) (string, error) {
+	if len(args) == 0 || (len(args) == 1 && args[0] == "-") {
+		b, err := io.ReadAll(in)
+		return string(b), err
+	}
+
+	if len(args) == 1 {
+		if info, err := os.Stat(args[0]); err == nil && !info.IsDir() {
+			b, err := os.ReadFile(args[0])
+			return string(b), err
+		}
+	}
+
+	return strings.Join(args, " "), nil
+}
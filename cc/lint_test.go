@@ -0,0 +1,200 @@
+package cc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	t.Run("Valid messages pass", func(t *testing.T) {
+		testCases := []string{
+			"feat: add lint command",
+			"fix(cc): handle empty scope",
+			"refactor(cc)!: drop types array",
+			"docs: explain lint\n\nlonger explanation\n\nsecond paragraph",
+			"feat(cc)!: change params\n\nbody\n\nBREAKING CHANGE: footer is required\nRefs: #12",
+			"feat: add footer\n\nCloses #12",
+			"# comment\nfix: strip comments\n# another comment\n",
+			"fix: scissors\n" + scissors + "\ndiff --git a/cc.go b/cc.go",
+			"Merge branch 'main' into feature",
+			"fixup! feat: add lint command",
+			"feat: add lint\n\nBreaking changes are listed in the docs.",
+			"feat: add lint\n\nBreaking change detection is left to lint.\n\nRefs: #2",
+		}
+		for _, tC := range testCases {
+			if errs := defaultConfig().lint(tC); len(errs) != 0 {
+				t.Errorf("%q got errors %v", tC, errs)
+			}
+		}
+	})
+
+	t.Run("Invalid messages report rule and position", func(t *testing.T) {
+		testCases := []struct {
+			in   string
+			want LintError
+		}{
+			{
+				in:   "",
+				want: LintError{Line: 1, Col: 1, Rule: "header-empty"},
+			},
+			{
+				in:   "add lint command",
+				want: LintError{Line: 1, Col: 1, Rule: "header-format"},
+			},
+			{
+				in:   ": add lint command",
+				want: LintError{Line: 1, Col: 1, Rule: "type-empty"},
+			},
+			{
+				in:   "feature: add lint command",
+				want: LintError{Line: 1, Col: 1, Rule: "type-enum"},
+			},
+			{
+				in:   "feat(cc: add lint command",
+				want: LintError{Line: 1, Col: 5, Rule: "scope-format"},
+			},
+			{
+				in:   "feat(): add lint command",
+				want: LintError{Line: 1, Col: 6, Rule: "scope-empty"},
+			},
+			{
+				in:   "feat(cc)? add lint: command",
+				want: LintError{Line: 1, Col: 9, Rule: "header-format"},
+			},
+			{
+				in:   "feat(cc):add lint command",
+				want: LintError{Line: 1, Col: 10, Rule: "header-format"},
+			},
+			{
+				in:   "feat(cc): ",
+				want: LintError{Line: 1, Col: 11, Rule: "subject-empty"},
+			},
+			{
+				in:   "feat: add lint\nbody",
+				want: LintError{Line: 2, Col: 1, Rule: "body-leading-blank"},
+			},
+			{
+				in:   "feat: add lint\n\nbody\nBREAKING CHANGE: not a footer",
+				want: LintError{Line: 4, Col: 1, Rule: "footer-leading-blank"},
+			},
+			{
+				in:   "feat: add lint\n\nbreaking change: lowercase",
+				want: LintError{Line: 3, Col: 1, Rule: "breaking-change-format"},
+			},
+			{
+				in:   "feat: add lint\n\nBREAKING CHANGE missing colon",
+				want: LintError{Line: 3, Col: 1, Rule: "breaking-change-format"},
+			},
+			{
+				in:   "feat: add lint\n\nBREAKING-CHANGE:missing space",
+				want: LintError{Line: 3, Col: 1, Rule: "breaking-change-format"},
+			},
+			{
+				in:   "# comment\nfeat: add lint\n\nBREAKING CHANGE: ",
+				want: LintError{Line: 4, Col: 18, Rule: "breaking-change-empty"},
+			},
+		}
+		for _, tC := range testCases {
+			t.Run(tC.want.Rule, func(t *testing.T) {
+				errs := defaultConfig().lint(tC.in)
+
+				if len(errs) != 1 {
+					t.Fatalf("got %d errors %v want 1", len(errs), errs)
+				}
+
+				got := *errs[0]
+				got.Message = ""
+
+				if got != tC.want {
+					t.Errorf("got %+v want %+v", got, tC.want)
+				}
+			})
+		}
+	})
+
+	t.Run("Message parsed into CC", func(t *testing.T) {
+		cc, errs := parseMessage("feat(cc)!: add lint\n\nfirst\n\nsecond\n\nRefs: #1\nCloses #2")
+		if len(errs) != 0 {
+			t.Fatalf("got errors %v", errs)
+		}
+
+		got := []string{cc.Type, cc.Scope, cc.Subject, cc.Body, cc.footer()}
+		want := []string{"feat", "cc", "add lint", "first\n\nsecond", "Refs: #1\nCloses #2"}
+
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("got %q want %q", got[i], want[i])
+			}
+		}
+
+		if !cc.Breaking {
+			t.Errorf("breaking not set")
+		}
+	})
+}
+
+func TestLintMessage(t *testing.T) {
+	buffer, cli, _ := mockCLI()
+
+	ok := cli.lintMessage("feature: add lint")
+
+	if ok {
+		t.Errorf("invalid message passed")
+	}
+
+	got := buffer.String()
+	want := "Commit message does not follow the conventional commit format:\n" +
+		"  1:1: type \"feature\" must be one of: build, ci, docs, feat, fix, refactor, test, chore [type-enum]\n"
+
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	if err := os.WriteFile(path, []byte("feat: from file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc string
+		args []string
+		want string
+	}{
+		{
+			desc: "stdin",
+			args: nil,
+			want: "feat: from stdin",
+		},
+		{
+			desc: "stdin dash",
+			args: []string{"-"},
+			want: "feat: from stdin",
+		},
+		{
+			desc: "file",
+			args: []string{path},
+			want: "feat: from file",
+		},
+		{
+			desc: "arguments",
+			args: []string{"feat:", "from", "args"},
+			want: "feat: from args",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := readMessage(strings.NewReader("feat: from stdin"), tC.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tC.want {
+				t.Errorf("got %q want %q", got, tC.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package cc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// coauthorToken is the token of the footer crediting a co-author.
+	coauthorToken = "Co-authored-by"
+	// maxAuthorCommits is the number of recent commits the authors offered as
+	// co-authors are read from.
+	maxAuthorCommits = 500
+)
+
+// coauthorFormat matches a co-author written as Name <email>.
+var coauthorFormat = regexp.MustCompile(`^[^<>]+ <[^<>\s]+@[^<>\s]+>$`)
+
+// coauthorCandidates returns the co-authors that can be chosen: the roster of the
+// coauthors config, followed by the authors of recent commits. Authors with the
+// same email are offered once, and the current user is left out.
+func (c *CLI) coauthorCandidates() []option {
+	seen := map[string]bool{}
+	if email, err := c.ce.output("config", "user.email"); err == nil && email != "" {
+		seen[strings.ToLower(email)] = true
+	}
+
+	var options []option
+	add := func(author, description string) {
+		author = strings.TrimSpace(author)
+		if !coauthorFormat.MatchString(author) {
+			return
+		}
+
+		_, email, _ := strings.Cut(author, "<")
+		email = strings.ToLower(strings.TrimSuffix(email, ">"))
+		if seen[email] {
+			return
+		}
+		seen[email] = true
+		options = append(options, option{author, description})
+	}
+
+	for _, author := range c.cfg.Coauthors {
+		add(author, "roster")
+	}
+
+	out, err := c.ce.output("log", "-n", strconv.Itoa(maxAuthorCommits), "--format=%an <%ae>")
+	if err == nil {
+		for _, author := range strings.Split(out, "\n") {
+			add(author, "recent author")
+		}
+	}
+	return options
+}
+
+// pairFile returns the file the co-authors are remembered in, which is inside the
+// git directory of the current repository, so that the prepare-commit-msg hook
+// and every shell of the repository credit the same co-authors.
+func (c *CLI) pairFile() (string, error) {
+	return c.ce.output("rev-parse", "--git-path", "cc/coauthors")
+}
+
+// rememberedCoauthors returns the co-authors chosen earlier in the repository,
+// until solo is passed.
+func (c *CLI) rememberedCoauthors() []string {
+	path, err := c.pairFile()
+	if err != nil {
+		return nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var coauthors []string
+	for _, s := range strings.Split(string(b), "\n") {
+		if s != "" {
+			coauthors = append(coauthors, s)
+		}
+	}
+	return coauthors
+}
+
+// rememberCoauthors remembers the co-authors for the next commits of the
+// repository, and forgets them when there are none.
+func (c *CLI) rememberCoauthors(coauthors []string) error {
+	path, err := c.pairFile()
+	if err != nil {
+		return err
+	}
+
+	if len(coauthors) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(coauthors, "\n")+"\n"), 0644)
+}
+
+// readCoauthors adds a Co-authored-by footer for every co-author. With the pair
+// parameter they are prompted for, and the choice is remembered for the next
+// commits. Otherwise the co-authors chosen earlier are added, unless the solo
+// parameter was passed, which forgets them.
+func (c *CLI) readCoauthors() {
+	remembered := c.rememberedCoauthors()
+	coauthors := remembered
+
+	switch {
+	case c.solo:
+		coauthors = nil
+	case c.pair && c.pick != nil:
+		coauthors = c.pickCoauthors(remembered)
+	case c.pair:
+		coauthors = c.promptCoauthors(remembered)
+	}
+
+	if c.pair || c.solo {
+		if err := c.rememberCoauthors(coauthors); err != nil {
+			fmt.Fprintf(c.Out, "Error remembering co-authors: %s\n", err)
+		}
+	} else if len(coauthors) > 0 {
+		fmt.Fprintf(c.Out, "Pairing with %s, pass solo to stop\n", strings.Join(coauthors, ", "))
+	}
+
+	c.addCoauthors(coauthors)
+}
+
+// addCoauthors adds a Co-authored-by footer for every co-author the CC does not
+// already credit.
+func (c *CLI) addCoauthors(coauthors []string) {
+	credited := map[string]bool{}
+	for _, t := range c.cc.Footers {
+		if strings.EqualFold(t.Token, coauthorToken) {
+			credited[strings.ToLower(t.Value)] = true
+		}
+	}
+
+	for _, author := range coauthors {
+		if !credited[strings.ToLower(author)] {
+			credited[strings.ToLower(author)] = true
+			c.cc.Footers = append(c.cc.Footers, Trailer{Token: coauthorToken, Value: author}.gitTrailer())
+		}
+	}
+}
+
+// promptCoauthors writes the numbered candidates and returns the co-authors
+// entered, separated by commas, as numbers, as filters that choose the best
+// matching candidate or as new co-authors written as Name <email>. Several
+// numbers may be separated by spaces. An empty input keeps the current
+// co-authors, and - removes them.
+func (c *CLI) promptCoauthors(current []string) []string {
+	candidates := c.coauthorCandidates()
+	for i, o := range candidates {
+		fmt.Fprintf(c.Out, "%-4s\033[36;1m%s\033[0m  %s\n", strconv.Itoa(i)+".", o.name, o.description)
+	}
+
+	fmt.Fprint(c.Out, withCurrent("\nEnter co-authors by number or name, separated by commas: ", strings.Join(current, ", ")))
+
+	input := strings.TrimSpace(c.readLine())
+	switch input {
+	case "":
+		return current
+	case "-":
+		return nil
+	}
+
+	var coauthors []string
+	for _, part := range strings.Split(input, ",") {
+		for _, author := range c.parseCoauthors(strings.TrimSpace(part), candidates) {
+			if !containsFold(coauthors, author) {
+				coauthors = append(coauthors, author)
+			}
+		}
+	}
+	return coauthors
+}
+
+// parseCoauthors returns the co-authors one comma separated part of the input
+// names, writing the parts that name none of the candidates.
+func (c *CLI) parseCoauthors(part string, candidates []option) []string {
+	if part == "" {
+		return nil
+	}
+	if coauthorFormat.MatchString(part) {
+		return []string{part}
+	}
+
+	if numbers, ok := parseNumbers(part); ok {
+		var numbered []string
+		for _, n := range numbers {
+			if n < 0 || n >= len(candidates) {
+				fmt.Fprintf(c.Out, "No co-author numbered %d\n", n)
+				continue
+			}
+			numbered = append(numbered, candidates[n].name)
+		}
+		return numbered
+	}
+
+	if matches := filterOptions(part, candidates); len(matches) > 0 {
+		return []string{matches[0].name}
+	}
+	fmt.Fprintf(c.Out, "No co-author matches %q, enter a new one as Name <email>\n", part)
+	return nil
+}
+
+// parseNumbers returns the space separated numbers of s, reporting whether every
+// field of s is a number.
+func parseNumbers(s string) ([]int, bool) {
+	var numbers []int
+	for _, field := range strings.Fields(s) {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, false
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, true
+}
+
+// pickCoauthors chooses co-authors one at a time in the picker, until it is left.
+// A co-author that is not a candidate can be typed in as Name <email>, and -
+// removes the ones chosen so far.
+func (c *CLI) pickCoauthors(current []string) []string {
+	candidates := c.coauthorCandidates()
+	coauthors := append([]string(nil), current...)
+
+	for {
+		title := "Choose a co-author, type to filter or to enter a new one as Name <email>, esc when done"
+		if len(coauthors) > 0 {
+			title += " [" + strings.Join(coauthors, ", ") + "]"
+		}
+
+		var options []option
+		for _, o := range candidates {
+			if !containsFold(coauthors, o.name) {
+				options = append(options, o)
+			}
+		}
+
+		author, ok := c.pick(title, options, "", true)
+		if !ok {
+			break
+		}
+
+		switch {
+		case author == "-":
+			coauthors = nil
+		case !coauthorFormat.MatchString(author):
+			fmt.Fprintf(c.Out, "A co-author must be written as Name <email>, not %s\n", author)
+		case !containsFold(coauthors, author):
+			coauthors = append(coauthors, author)
+		}
+	}
+
+	if len(coauthors) > 0 {
+		fmt.Fprintf(c.Out, "Co-authors: \033[36;1m%s\033[0m\n", strings.Join(coauthors, ", "))
+	}
+	return coauthors
+}
+
+// containsFold reports whether values contains s, ignoring case.
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
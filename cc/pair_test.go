@@ -0,0 +1,136 @@
+package cc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// pairLog is the git log output of the authors of recent commits, including the
+// current user and an author with a different spelling of the same email.
+const pairLog = "Me <me@example.com>\nSam Lee <sam@example.com>\nAlex Kim <alex@example.com>\nSam <SAM@example.com>"
+
+// pairRoster is the coauthors config, including an entry that is not an author.
+var pairRoster = []string{"Jane Doe <jane@example.com>", "not an author"}
+
+// pairOutputs returns the git outputs of a repository remembering co-authors in a
+// temporary git directory, along with the file they are remembered in.
+func pairOutputs(t *testing.T) (string, map[string]string) {
+	path := filepath.Join(t.TempDir(), "cc", "coauthors")
+	return path, map[string]string{
+		"config user.email":                 "me@example.com",
+		"log -n 500 --format=%an <%ae>":     pairLog,
+		"rev-parse --git-path cc/coauthors": path,
+	}
+}
+
+// coauthorFooters returns the values of the Co-authored-by footers of the CC.
+func coauthorFooters(cc *CC) []string {
+	var values []string
+	for _, t := range cc.Footers {
+		if t.Token == coauthorToken {
+			values = append(values, t.Value)
+		}
+	}
+	return values
+}
+
+// sequencePicker returns a picker that chooses the values one after another,
+// and is left once they run out.
+func sequencePicker(values ...string) picker {
+	return func(title string, options []option, current string, allowNew bool) (string, bool) {
+		if len(values) == 0 {
+			return "", false
+		}
+		value := values[0]
+		values = values[1:]
+		return value, true
+	}
+}
+
+func TestCoauthorCandidates(t *testing.T) {
+	_, outputs := pairOutputs(t)
+	_, cli, _ := mockGitCLI(outputs)
+	cli.cfg.Coauthors = pairRoster
+
+	want := []option{
+		{"Jane Doe <jane@example.com>", "roster"},
+		{"Sam Lee <sam@example.com>", "recent author"},
+		{"Alex Kim <alex@example.com>", "recent author"},
+	}
+	if got := cli.coauthorCandidates(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestReadCoauthors(t *testing.T) {
+	sam := []string{"Sam Lee <sam@example.com>"}
+
+	testCases := []struct {
+		desc       string
+		remembered []string
+		input      string
+		picks      []string
+		solo       bool
+		want       []string
+	}{
+		{desc: "By number", input: "0 2", want: []string{"Jane Doe <jane@example.com>", "Alex Kim <alex@example.com>"}},
+		{desc: "By name", input: "sam, jane", want: []string{"Sam Lee <sam@example.com>", "Jane Doe <jane@example.com>"}},
+		{desc: "New co-author", input: "Kai <kai@example.com>, 9", want: []string{"Kai <kai@example.com>"}},
+		{desc: "Kept", remembered: sam, input: "", want: sam},
+		{desc: "None", remembered: sam, input: "-", want: nil},
+		{
+			desc:  "Picker",
+			picks: []string{"Alex Kim <alex@example.com>", "alex", "Kai <kai@example.com>"},
+			want:  []string{"Alex Kim <alex@example.com>", "Kai <kai@example.com>"},
+		},
+		{desc: "Solo", remembered: sam, solo: true, want: nil},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			path, outputs := pairOutputs(t)
+			_, cli, _ := mockGitCLI(outputs, tC.input)
+			cli.cfg.Coauthors = pairRoster
+			if err := cli.rememberCoauthors(tC.remembered); err != nil {
+				t.Fatal(err)
+			}
+			if tC.picks != nil {
+				cli.pick = sequencePicker(tC.picks...)
+			}
+			cli.pair, cli.solo = !tC.solo, tC.solo
+
+			cli.readCoauthors()
+
+			if got := coauthorFooters(cli.cc); !reflect.DeepEqual(got, tC.want) {
+				t.Errorf("got %v want %v", got, tC.want)
+			}
+			if got := cli.rememberedCoauthors(); !reflect.DeepEqual(got, tC.want) {
+				t.Errorf("remembered %v want %v", got, tC.want)
+			}
+			if _, err := os.Stat(path); (err == nil) != (tC.want != nil) {
+				t.Errorf("got %v for the pair file", err)
+			}
+		})
+	}
+
+	t.Run("Remembered for the repository", func(t *testing.T) {
+		path, outputs := pairOutputs(t)
+		_, cli, _ := mockGitCLI(outputs)
+		if err := cli.rememberCoauthors(sam); err != nil {
+			t.Fatal(err)
+		}
+		cli.cc.Footers = []Trailer{{coauthorToken, ": ", "sam lee <sam@example.com>"}}
+
+		cli.readCoauthors()
+
+		if got := coauthorFooters(cli.cc); len(got) != 1 {
+			t.Errorf("got %v want the co-author credited once", got)
+		}
+
+		_, hook, _ := mockGitCLI(map[string]string{"rev-parse --git-path cc/coauthors": path})
+		if got := hook.rememberedCoauthors(); !reflect.DeepEqual(got, sam) {
+			t.Errorf("got %v want %v from another process", got, sam)
+		}
+	})
+}
@@ -0,0 +1,318 @@
+package cc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// option is a value offered by the picker, along with a description of it.
+type option struct {
+	name        string
+	description string
+}
+
+// picker lets the user choose one of options, starting on the current value, or
+// enter a value matching none of them when allowNew is set. It returns false when
+// the user keeps the current value.
+type picker func(title string, options []option, current string, allowNew bool) (string, bool)
+
+// pickerKey is what a key pressed in the picker does.
+type pickerKey int
+
+const (
+	keyNone pickerKey = iota
+	keySelect
+	keyCancel
+	keyInterrupt
+)
+
+// pickerState is the filter typed into the picker, the options matching it and the
+// option the cursor is on.
+type pickerState struct {
+	options  []option
+	allowNew bool
+	filter   []rune
+	matches  []option
+	cursor   int
+}
+
+// newPickerState returns the state of a picker showing every option, with the
+// cursor on the current value when it is one of them.
+func newPickerState(options []option, current string, allowNew bool) *pickerState {
+	s := &pickerState{options: options, allowNew: allowNew}
+	s.update()
+
+	for i, o := range s.matches {
+		if o.name == current {
+			s.cursor = i
+		}
+	}
+	return s
+}
+
+// update finds the options matching the filter, best matches first, and keeps the
+// cursor on one of them. When new values are allowed, a filter that names no option
+// is offered as a new value after the matches.
+func (s *pickerState) update() {
+	filter := string(s.filter)
+	s.matches = filterOptions(filter, s.options)
+
+	if s.allowNew && filter != "" {
+		exists := false
+		for _, o := range s.options {
+			if o.name == filter {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			s.matches = append(s.matches, option{name: filter, description: "new"})
+		}
+	}
+
+	if s.cursor >= len(s.matches) {
+		s.cursor = len(s.matches) - 1
+	}
+	if s.cursor < 0 {
+		s.cursor = 0
+	}
+}
+
+// handle updates the state for a key read from the terminal and returns what the
+// key does. The arrow keys, Ctrl-P and Ctrl-N move the cursor, Backspace and Ctrl-U
+// edit the filter, and any other printable key is added to the filter. Other escape
+// sequences are ignored.
+func (s *pickerState) handle(key string) pickerKey {
+	switch key {
+	case "\x1b[A", "\x1bOA", "\x10":
+		if s.cursor > 0 {
+			s.cursor--
+		}
+	case "\x1b[B", "\x1bOB", "\x0e":
+		if s.cursor < len(s.matches)-1 {
+			s.cursor++
+		}
+	case "\r", "\n":
+		if len(s.matches) > 0 {
+			return keySelect
+		}
+	case "\x1b", "\x04":
+		return keyCancel
+	case "\x03":
+		return keyInterrupt
+	case "\x7f", "\x08":
+		if len(s.filter) > 0 {
+			s.filter = s.filter[:len(s.filter)-1]
+			s.update()
+		}
+	case "\x15":
+		s.filter = nil
+		s.update()
+	default:
+		r, _ := utf8.DecodeRuneInString(key)
+		if unicode.IsPrint(r) {
+			s.filter = append(s.filter, r)
+			s.cursor = 0
+			s.update()
+		}
+	}
+	return keyNone
+}
+
+// splitKeys splits what was read from the terminal into the keys pressed, as keys
+// typed quickly or pasted are read together. A key is a character, or an escape
+// sequence such as the one sent for an arrow key.
+func splitKeys(input string) []string {
+	var keys []string
+	for input != "" {
+		n := 1
+		switch {
+		case strings.HasPrefix(input, "\x1b[") || strings.HasPrefix(input, "\x1bO"):
+			n = 2
+			for n < len(input) && (input[n] < 0x40 || input[n] > 0x7e) {
+				n++
+			}
+			if n < len(input) {
+				n++
+			}
+		case input[0] != '\x1b':
+			_, n = utf8.DecodeRuneInString(input)
+		}
+		keys = append(keys, input[:n])
+		input = input[n:]
+	}
+	return keys
+}
+
+// selected returns the name of the option the cursor is on.
+func (s *pickerState) selected() string {
+	if len(s.matches) == 0 {
+		return ""
+	}
+	return s.matches[s.cursor].name
+}
+
+// filterOptions returns the options whose name matches filter, or whose description
+// contains it, ordered by how well they match and then by their order in options.
+func filterOptions(filter string, options []option) []option {
+	type match struct {
+		option
+		score int
+	}
+
+	var matches []match
+	for _, o := range options {
+		score, ok := fuzzyMatch(filter, o.name)
+		if !ok && strings.Contains(strings.ToLower(o.description), strings.ToLower(filter)) {
+			score, ok = 3, true
+		}
+		if ok {
+			matches = append(matches, match{o, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score < matches[j].score
+	})
+
+	filtered := make([]option, len(matches))
+	for i, m := range matches {
+		filtered[i] = m.option
+	}
+	return filtered
+}
+
+// fuzzyMatch reports whether the characters of pattern appear in s in order,
+// ignoring case. The score ranks prefixes first, then other substrings, then
+// scattered characters.
+func fuzzyMatch(pattern, s string) (int, bool) {
+	pattern, s = strings.ToLower(pattern), strings.ToLower(s)
+
+	switch {
+	case strings.HasPrefix(s, pattern):
+		return 0, true
+	case strings.Contains(s, pattern):
+		return 1, true
+	}
+
+	rest := s
+	for _, r := range pattern {
+		i := strings.IndexRune(rest, r)
+		if i < 0 {
+			return 0, false
+		}
+		rest = rest[i+utf8.RuneLen(r):]
+	}
+	return 2, true
+}
+
+// isTerminal reports whether r and w are both a terminal, so that the picker can be
+// shown instead of the numbered prompts.
+func isTerminal(r io.Reader, w io.Writer) bool {
+	in, ok := r.(*os.File)
+	if !ok || !term.IsTerminal(int(in.Fd())) {
+		return false
+	}
+	out, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(out.Fd()))
+}
+
+// terminalPicker returns a picker that takes over the terminal with the alternate
+// screen while the user chooses, reading keys from in in raw mode. Pressing Ctrl-C
+// restores the terminal before interrupting the CLI.
+func (c *CLI) terminalPicker(in *os.File) picker {
+	return func(title string, options []option, current string, allowNew bool) (string, bool) {
+		fd := int(in.Fd())
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return "", false
+		}
+
+		fmt.Fprint(c.Out, "\033[?1049h")
+		restore := func() {
+			fmt.Fprint(c.Out, "\033[?1049l")
+			term.Restore(fd, state)
+		}
+
+		s := newPickerState(options, current, allowNew)
+		buf := make([]byte, 256)
+		for {
+			_, height, err := term.GetSize(fd)
+			if err != nil || height == 0 {
+				height = 24
+			}
+			c.renderPicker(title, s, height)
+
+			n, err := in.Read(buf)
+			if err != nil {
+				restore()
+				return "", false
+			}
+
+			for _, key := range splitKeys(string(buf[:n])) {
+				switch s.handle(key) {
+				case keySelect:
+					restore()
+					return s.selected(), true
+				case keyCancel:
+					restore()
+					return "", false
+				case keyInterrupt:
+					restore()
+					c.interrupt()
+				}
+			}
+		}
+	}
+}
+
+// renderPicker draws the picker on the whole screen: the title, the filter, as many
+// of the matching options around the cursor as fit, and the keys to use.
+func (c *CLI) renderPicker(title string, s *pickerState, height int) {
+	width := 0
+	for _, o := range s.matches {
+		if len(o.name) > width {
+			width = len(o.name)
+		}
+	}
+
+	rows := height - 5
+	if rows < 1 {
+		rows = 1
+	}
+	first := 0
+	if s.cursor >= rows {
+		first = s.cursor - rows + 1
+	}
+	last := first + rows
+	if last > len(s.matches) {
+		last = len(s.matches)
+	}
+
+	var b strings.Builder
+	b.WriteString("\033[H\033[2J")
+	b.WriteString(title + "\r\n\r\n")
+	for i := first; i < last; i++ {
+		o := s.matches[i]
+		padding := strings.Repeat(" ", width-len(o.name)+2)
+		if i == s.cursor {
+			b.WriteString("> \033[36;1m" + o.name + "\033[0m" + padding + o.description + "\r\n")
+		} else {
+			b.WriteString("  " + o.name + padding + "\033[2m" + o.description + "\033[0m\r\n")
+		}
+	}
+	if len(s.matches) == 0 {
+		b.WriteString("  \033[2mNothing matches\033[0m\r\n")
+	}
+	b.WriteString("\r\n\033[2m↑/↓ move, enter choose, esc keep current, ctrl-c quit\033[0m")
+	b.WriteString("\033[2;1H\033[K/ " + string(s.filter))
+
+	fmt.Fprint(c.Out, b.String())
+}
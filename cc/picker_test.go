@@ -0,0 +1,209 @@
+package cc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFilterOptions(t *testing.T) {
+	options := []option{
+		{"build", "Changes that affect the build system"},
+		{"feat", "A new feature"},
+		{"fix", "A bug fix"},
+		{"refactor", "A code change"},
+	}
+
+	testCases := []struct {
+		filter string
+		want   []string
+	}{
+		{filter: "", want: []string{"build", "feat", "fix", "refactor"}},
+		{filter: "f", want: []string{"feat", "fix", "refactor", "build"}},
+		{filter: "FX", want: []string{"fix"}},
+		{filter: "act", want: []string{"refactor"}},
+		{filter: "bug", want: []string{"fix"}},
+		{filter: "zz", want: []string{}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.filter, func(t *testing.T) {
+			got := []string{}
+			for _, o := range filterOptions(tC.filter, options) {
+				got = append(got, o.name)
+			}
+
+			if !reflect.DeepEqual(got, tC.want) {
+				t.Errorf("got %q want %q", got, tC.want)
+			}
+		})
+	}
+}
+
+func TestPickerState(t *testing.T) {
+	options := []option{{"aws", ""}, {"cc", ""}, {"ssh", ""}}
+
+	t.Run("Arrow keys move the cursor", func(t *testing.T) {
+		s := newPickerState(options, "", false)
+
+		for _, key := range []string{"\x1b[B", "\x1b[B", "\x1b[B", "\x1b[A"} {
+			s.handle(key)
+		}
+
+		if got := s.selected(); got != "cc" {
+			t.Errorf("got %q want %q", got, "cc")
+		}
+		if s.handle("\r") != keySelect {
+			t.Errorf("got no selection on enter")
+		}
+	})
+
+	t.Run("Cursor starts on the current value", func(t *testing.T) {
+		s := newPickerState(options, "ssh", false)
+
+		if got := s.selected(); got != "ssh" {
+			t.Errorf("got %q want %q", got, "ssh")
+		}
+	})
+
+	t.Run("Typing filters the options", func(t *testing.T) {
+		s := newPickerState(options, "", false)
+
+		s.handle("s")
+		s.handle("h")
+
+		if got := s.selected(); got != "ssh" {
+			t.Errorf("got %q want %q", got, "ssh")
+		}
+
+		s.handle("\x15")
+		if len(s.matches) != len(options) {
+			t.Errorf("got %d matches want every option after clearing", len(s.matches))
+		}
+	})
+
+	t.Run("New value offered when allowed", func(t *testing.T) {
+		s := newPickerState(options, "", true)
+
+		s.handle("c")
+		s.handle("m")
+		s.handle("d")
+
+		if got := s.selected(); got != "cmd" {
+			t.Errorf("got %q want %q", got, "cmd")
+		}
+
+		s.handle("\x7f")
+		s.handle("\x7f")
+		if got := s.selected(); got != "cc" {
+			t.Errorf("got %q want %q", got, "cc")
+		}
+	})
+
+	t.Run("Nothing selected without a match", func(t *testing.T) {
+		s := newPickerState(options, "", false)
+
+		s.handle("z")
+
+		if s.handle("\r") != keyNone {
+			t.Errorf("got a selection with no matches")
+		}
+		if s.handle("\x1b") != keyCancel {
+			t.Errorf("got no cancel on escape")
+		}
+		if s.handle("\x03") != keyInterrupt {
+			t.Errorf("got no interrupt on ctrl-c")
+		}
+	})
+}
+
+func TestSplitKeys(t *testing.T) {
+	got := splitKeys("ab\x1b[B\r\x1bOA\x1b[1;5C\x1bé")
+	want := []string{"a", "b", "\x1b[B", "\r", "\x1bOA", "\x1b[1;5C", "\x1b", "é"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+// fakePicker returns a picker that chooses value, or keeps the current value when
+// it is empty, and records the options it was shown.
+func fakePicker(value string, shown *[]option) picker {
+	return func(title string, options []option, current string, allowNew bool) (string, bool) {
+		*shown = options
+		return value, value != ""
+	}
+}
+
+func TestPickType(t *testing.T) {
+	t.Run("Type chosen in the picker", func(t *testing.T) {
+		var shown []option
+		buffer, cli, _ := mockCLI()
+		cli.pick = fakePicker("fix", &shown)
+
+		if err := cli.promptType(); err != nil {
+			t.Fatal(err)
+		}
+
+		if cli.cc.Type != "fix" {
+			t.Errorf("got %q want %q", cli.cc.Type, "fix")
+		}
+		if len(shown) != len(defaultTypes) {
+			t.Errorf("got %d options want %d", len(shown), len(defaultTypes))
+		}
+		if got, want := buffer.String(), "Type: \033[36;1mfix\033[0m\n"; got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("Leaving the picker without a type", func(t *testing.T) {
+		var shown []option
+		_, cli, _ := mockCLI()
+		cli.pick = fakePicker("", &shown)
+
+		if err := cli.promptType(); err == nil {
+			t.Errorf("got no error want no type chosen")
+		}
+	})
+}
+
+func TestPickScope(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cc", "scopes")
+
+	var shown []option
+	_, cli, _ := mockGitCLI(map[string]string{
+		"diff --cached --name-only":      "ssh/ssh.go\ncc/cc.go",
+		"rev-parse --git-path cc/scopes": path,
+	})
+	cli.pick = fakePicker("picker", &shown)
+
+	for _, scope := range []string{"cc", "changelog", "lint"} {
+		cli.cc.Scope = scope
+		cli.rememberScope()
+	}
+
+	cli.readScope()
+
+	want := []option{
+		{"cc", "staged files"},
+		{"ssh", "staged files"},
+		{"lint", "used before"},
+		{"changelog", "used before"},
+	}
+	if !reflect.DeepEqual(shown, want) {
+		t.Errorf("got %q want %q", shown, want)
+	}
+
+	if cli.cc.Scope != "picker" {
+		t.Errorf("got %q want %q", cli.cc.Scope, "picker")
+	}
+
+	cli.rememberScope()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "picker\nlint\nchangelog\ncc\n"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
@@ -0,0 +1,144 @@
+package cc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// inProgress are the files git keeps while a merge or a rebase is in progress,
+// along with the operation they belong to.
+var inProgress = []struct {
+	path      string
+	operation string
+}{
+	{"MERGE_HEAD", "merge"},
+	{"rebase-merge", "rebase"},
+	{"rebase-apply", "rebase"},
+}
+
+// checkRepository checks that a commit can be made before prompting for it: the
+// current directory is inside a work tree and no merge or rebase is in progress.
+// Signing is left to git, which finds the key in more ways than user.signingkey.
+func (c *CLI) checkRepository() error {
+	if out, err := c.ce.output("rev-parse", "--is-inside-work-tree"); err != nil || out != "true" {
+		fmt.Fprintln(c.Out, "Not inside a git work tree")
+		return &CCError{"Not inside a work tree"}
+	}
+
+	for _, p := range inProgress {
+		path, err := c.ce.output("rev-parse", "--git-path", p.path)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			fmt.Fprintf(c.Out, "A %s is in progress, finish or abort it before making a commit\n", p.operation)
+			return &CCError{"Operation in progress"}
+		}
+	}
+
+	return nil
+}
+
+// checkStaged checks that there are staged changes to commit. When there are none
+// but the work tree has changes, the user is offered to stage them with git add -p,
+// or to stage every change to tracked files the way git commit -a does.
+func (c *CLI) checkStaged() error {
+	if len(c.stagedFiles()) > 0 {
+		return nil
+	}
+
+	if status, err := c.ce.output("status", "--porcelain"); err != nil || status == "" {
+		fmt.Fprintln(c.Out, "Nothing to commit, the work tree is clean")
+		return &CCError{"Nothing to commit"}
+	}
+
+	if c.yes {
+		fmt.Fprintln(c.Out, "Nothing is staged, stage changes with git add before committing")
+		return &CCError{"Nothing staged"}
+	}
+
+	fmt.Fprint(c.Out, "Nothing is staged. Stage changes with git add [p]atch, stage [a]ll tracked files, or [q]uit: ")
+
+	var cmd *exec.Cmd
+	switch strings.ToLower(c.readLine()) {
+	case "p", "patch":
+		cmd = c.ce.add("--patch")
+	case "a", "all":
+		cmd = c.ce.add("--update")
+	default:
+		return &CCError{"Nothing staged"}
+	}
+
+	cmd.Stdin = c.stdin
+	cmd.Stdout = c.Out
+	cmd.Stderr = c.Out
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(c.Out, "Error staging changes: %s\n", err)
+		return err
+	}
+
+	if len(c.stagedFiles()) == 0 {
+		fmt.Fprintln(c.Out, "Nothing was staged")
+		return &CCError{"Nothing staged"}
+	}
+	return nil
+}
+
+// runGit runs a git command, writing its output and error output as it goes, so
+// that prompts of hooks and signing programs are shown. When git fails, its exit
+// status is written along with what was being done, and its error output is kept
+// in the returned error.
+func (c *CLI) runGit(cmd *exec.Cmd, action string) error {
+	out := c.Out
+	if _, ok := out.(*os.File); !ok {
+		out = &syncWriter{w: out}
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stdout = out
+	cmd.Stderr = io.MultiWriter(out, &stderr)
+
+	err := cmd.Run()
+	if err == nil {
+		return nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitErr.Stderr = stderr.Bytes()
+		fmt.Fprintf(c.Out, "Error %s, git exited with status %d\n", action, exitErr.ExitCode())
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "Error %s: %s\n", action, err)
+	return err
+}
+
+// syncWriter serializes writes to a writer that is not a file, which the output and
+// the error output of git are copied to at the same time.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (sw *syncWriter) Write(p []byte) (int, error) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return sw.w.Write(p)
+}
+
+// exitStatus returns the exit status of a failed git command, or 1 for any other
+// error, so that cc exits with the same status as git.
+func exitStatus(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+	return 1
+}
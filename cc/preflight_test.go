@@ -0,0 +1,152 @@
+package cc
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckRepository(t *testing.T) {
+	dir := t.TempDir()
+	mergeHead := filepath.Join(dir, "MERGE_HEAD")
+	if err := os.WriteFile(mergeHead, []byte("aaa\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc    string
+		outputs map[string]string
+		signed  bool
+		want    string
+	}{
+		{
+			desc:    "Outside of a work tree",
+			outputs: map[string]string{},
+			want:    "Not inside a git work tree\n",
+		},
+		{
+			desc: "Merge in progress",
+			outputs: map[string]string{
+				"rev-parse --is-inside-work-tree":   "true",
+				"rev-parse --git-path MERGE_HEAD":   mergeHead,
+				"rev-parse --git-path rebase-merge": filepath.Join(dir, "rebase-merge"),
+			},
+			want: "A merge is in progress, finish or abort it before making a commit\n",
+		},
+		{
+			desc: "Signed without user.signingkey",
+			outputs: map[string]string{
+				"rev-parse --is-inside-work-tree": "true",
+			},
+			signed: true,
+			want:   "",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			buffer, cli, ce := mockCLI()
+			ce.outputs = tC.outputs
+			cli.signed = tC.signed
+
+			err := cli.checkRepository()
+
+			got := buffer.String()
+			if got != tC.want {
+				t.Errorf("got %q want %q", got, tC.want)
+			}
+
+			if (err != nil) != (tC.want != "") {
+				t.Errorf("got error %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckStaged(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		yes     bool
+		outputs map[string]string
+		command string
+		ok      bool
+	}{
+		{
+			desc:    "Changes staged",
+			outputs: map[string]string{"diff --cached --name-only": "cc/cc.go"},
+			ok:      true,
+		},
+		{
+			desc:    "Clean work tree",
+			outputs: map[string]string{"diff --cached --name-only": "", "status --porcelain": ""},
+		},
+		{
+			desc:    "Nothing staged without prompts",
+			yes:     true,
+			outputs: map[string]string{"diff --cached --name-only": "", "status --porcelain": " M cc/cc.go"},
+		},
+		{
+			desc:    "Patch offered",
+			input:   "p",
+			outputs: map[string]string{"diff --cached --name-only": "", "status --porcelain": " M cc/cc.go"},
+			command: "add --patch",
+		},
+		{
+			desc:    "All tracked files offered",
+			input:   "a",
+			outputs: map[string]string{"diff --cached --name-only": "", "status --porcelain": " M cc/cc.go"},
+			command: "add --update",
+		},
+		{
+			desc:    "Quit",
+			input:   "q",
+			outputs: map[string]string{"diff --cached --name-only": "", "status --porcelain": " M cc/cc.go"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			_, cli, ce := mockCLI(tC.input)
+			ce.outputs = tC.outputs
+			cli.yes = tC.yes
+
+			err := cli.checkStaged()
+
+			if (err == nil) != tC.ok {
+				t.Errorf("got error %v", err)
+			}
+
+			// the mock does not stage anything, so nothing is staged after git add
+			if ce.command != tC.command {
+				t.Errorf("got %q want %q", ce.command, tC.command)
+			}
+		})
+	}
+}
+
+func TestRunGit(t *testing.T) {
+	buffer, cli, _ := mockCLI()
+
+	err := cli.runGit(exec.Command("sh", "-c", "echo 'hook rejected the message' >&2; exit 3"), "making commit")
+
+	got := buffer.String()
+	want := "hook rejected the message\nError making commit, git exited with status 3\n"
+
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || string(exitErr.Stderr) != "hook rejected the message\n" {
+		t.Errorf("got %v want the error output kept", err)
+	}
+
+	if status := exitStatus(err); status != 3 {
+		t.Errorf("got %d want %d", status, 3)
+	}
+
+	if err := cli.runGit(exec.Command("true"), "making commit"); err != nil {
+		t.Errorf("got error %v for a successful command", err)
+	}
+}
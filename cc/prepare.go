@@ -0,0 +1,68 @@
+package cc
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ttyPath is the terminal the prompts are read from and written to when cc is
+// run by git as a hook, which does not connect the hook to the terminal.
+const ttyPath = "/dev/tty"
+
+// suppliesMessage reports whether git already supplied the commit message for the
+// source it passes to the prepare-commit-msg hook: message for -m or -F, merge,
+// squash, and commit for -c, -C or --amend. No source, or a template, means that
+// the message is still to be written.
+func suppliesMessage(source string) bool {
+	return source != "" && source != "template"
+}
+
+// commentLines returns the lines of a message that git removes as comments, such
+// as the status that git commit adds below the message. The scissors line and
+// everything below it, such as the diff of git commit -v, are kept unchanged.
+func commentLines(message string) string {
+	var comments []string
+	lines := strings.Split(strings.TrimSuffix(message, "\n"), "\n")
+	for i, line := range lines {
+		if line == scissors {
+			comments = append(comments, lines[i:]...)
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			comments = append(comments, line)
+		}
+	}
+	return strings.Join(comments, "\n")
+}
+
+// prepareMessage prompts for the fields of the conventional commit and writes the
+// message into the file git passed to the prepare-commit-msg hook, in place of
+// making the commit. The comments git wrote into the file are kept below it, while
+// the text of a commit template is replaced.
+func (c *CLI) prepareMessage(path string) error {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(c.Out, "Error reading commit message file: %s\n", err)
+		return err
+	}
+
+	c.readBranch()
+	if err := c.readMissingFields(); err != nil {
+		return err
+	}
+	c.buildMessage()
+	c.reviewStyle()
+
+	message := c.message + "\n"
+	if comments := commentLines(string(existing)); comments != "" {
+		message += "\n" + comments + "\n"
+	}
+
+	if err := os.WriteFile(path, []byte(message), 0644); err != nil {
+		fmt.Fprintf(c.Out, "Error writing commit message file: %s\n", err)
+		return err
+	}
+	c.rememberScope()
+	return nil
+}
@@ -0,0 +1,79 @@
+package cc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSuppliesMessage(t *testing.T) {
+	testCases := []struct {
+		source string
+		want   bool
+	}{
+		{source: "", want: false},
+		{source: "template", want: false},
+		{source: "message", want: true},
+		{source: "merge", want: true},
+		{source: "squash", want: true},
+		{source: "commit", want: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.source, func(t *testing.T) {
+			if got := suppliesMessage(tC.source); got != tC.want {
+				t.Errorf("got %t want %t", got, tC.want)
+			}
+		})
+	}
+}
+
+func TestPrepareMessage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	existing := "Template text\n\n# Please enter the commit message for your changes.\n#\n# Changes to be committed:\n#\tmodified:   cc/cc.go\n"
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer, cli, ce := mockCLI("3", "cc", "add prepare", "", "Closes #12", "")
+
+	if err := cli.prepareMessage(path); err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := os.ReadFile(path)
+	got := string(b)
+	want := "feat(cc): add prepare\n\nCloses: #12\n\n# Please enter the commit message for your changes.\n#\n# Changes to be committed:\n#\tmodified:   cc/cc.go\n"
+
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	if ce.command != "" {
+		t.Errorf("got %q want no commit", ce.command)
+	}
+
+	if len(buffer.String()) == 0 {
+		t.Errorf("got no prompts")
+	}
+}
+
+func TestPrepareVerboseMessage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	below := scissors + "\n# Do not modify or remove the line above.\n# Everything below it will be ignored.\ndiff --git a/cc/cc.go b/cc/cc.go\n--- a/cc/cc.go\n+++ b/cc/cc.go\n@@ -1 +1 @@\n-package cc\n+package cc // cc\n"
+	existing := "\n# Please enter the commit message for your changes.\n" + below
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, cli, _ := mockCLI("3", "cc", "add prepare", "", "", "")
+
+	if err := cli.prepareMessage(path); err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := os.ReadFile(path)
+	want := "feat(cc): add prepare\n\n# Please enter the commit message for your changes.\n" + below
+	if got := string(b); got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
@@ -0,0 +1,141 @@
+package cc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// revertType is the type of the commits that revert earlier commits, which is
+// accepted whether or not it is configured.
+const revertType = "revert"
+
+// revertedCommit matches the sentence of the body of a revert commit naming the
+// commit it reverts.
+var revertedCommit = regexp.MustCompile(`(?m)^This reverts commit ([0-9a-f]{7,40})\.`)
+
+// splitRevertArgs splits the arguments of the revert command into the commits to
+// revert and the parameters.
+func splitRevertArgs(args []string) ([]string, []string) {
+	var revs, params []string
+	for _, arg := range args {
+		switch arg {
+		case "signed", "yes":
+			params = append(params, arg)
+		default:
+			revs = append(revs, arg)
+		}
+	}
+	return revs, params
+}
+
+// revertCC returns the conventional commit reverting the commit sha with message,
+// whose header is the header of the reverted commit.
+func (cfg *config) revertCC(sha, message string) *CC {
+	message = cfg.untemplate(message)
+
+	header := strings.TrimSpace(message)
+	for _, line := range strings.Split(message, "\n") {
+		if line != "" && !strings.HasPrefix(line, "#") {
+			header = line
+			break
+		}
+	}
+	if cc, err := Parse(message); err == nil {
+		header, _, _ = strings.Cut(cc.String(), "\n")
+	}
+
+	return &CC{
+		Type:    revertType,
+		Subject: header,
+		Body:    "This reverts commit " + sha + ".",
+		Footers: []Trailer{{Token: ticketToken, Separator: ": ", Value: shortSHA(sha)}},
+	}
+}
+
+// revert reverts each of the commits in turn with git revert --no-commit, and
+// commits the revert with a conventional commit message that names the commit.
+// Reverting stops at a commit whose revert is not committed, leaving its changes
+// staged, or that git fails to revert, such as when it conflicts.
+func (c *CLI) revert(revs []string) error {
+	if len(revs) == 0 {
+		fmt.Fprintln(c.Out, "Missing the commits to revert")
+		return &CCError{"No commits to revert"}
+	}
+
+	if len(c.stagedFiles()) > 0 {
+		fmt.Fprintln(c.Out, "Commit or unstage the staged changes before reverting, they would be committed with the revert")
+		return &CCError{"Changes staged"}
+	}
+
+	var shas []string
+	for _, rev := range revs {
+		sha, err := c.ce.output("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+		if err != nil {
+			fmt.Fprintf(c.Out, "Not a commit: %s\n", rev)
+			return &CCError{"Not a commit"}
+		}
+		shas = append(shas, sha)
+	}
+
+	c.reverting = true
+	for _, sha := range shas {
+		message, err := c.ce.output("log", "-1", "--format=%B", sha)
+		if err != nil {
+			fmt.Fprintf(c.Out, "Error reading message of %s: %s\n", shortSHA(sha), err)
+			return err
+		}
+
+		if err := c.runGit(c.ce.revert(sha), "reverting "+shortSHA(sha)); err != nil {
+			fmt.Fprintln(c.Out, "Resolve the conflicts and commit the revert, or run git revert --abort")
+			return err
+		}
+
+		head, _ := c.ce.output("rev-parse", "HEAD")
+
+		c.cc = c.cfg.revertCC(sha, message)
+		c.buildMessage()
+		if err := c.makeCommit(); err != nil {
+			return err
+		}
+
+		if committed, err := c.ce.output("rev-parse", "HEAD"); err == nil && committed == head {
+			fmt.Fprintf(c.Out, "The revert of %s is staged, commit it or run git revert --abort\n", shortSHA(sha))
+			return nil
+		}
+	}
+	return nil
+}
+
+// cancelReverts leaves out the commits that are reverted by a later commit in the
+// same list, along with the commits reverting them, as neither changes anything.
+// The commits are newest first, so a revert that is itself reverted does not cancel
+// out the commit it reverted.
+func cancelReverts(commits []commit) []commit {
+	cancelled := map[string]bool{}
+	for i, cm := range commits {
+		if cm.cc.Type != revertType || cancelled[cm.sha] {
+			continue
+		}
+
+		m := revertedCommit.FindStringSubmatch(cm.cc.Body)
+		if m == nil {
+			continue
+		}
+		for _, reverted := range commits[i+1:] {
+			if strings.HasPrefix(reverted.sha, m[1]) && !cancelled[reverted.sha] {
+				cancelled[reverted.sha] = true
+				cancelled[cm.sha] = true
+				break
+			}
+		}
+	}
+
+	var kept []commit
+	for _, cm := range commits {
+		if !cancelled[cm.sha] {
+			kept = append(kept, cm)
+		}
+	}
+	return kept
+}
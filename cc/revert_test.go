@@ -0,0 +1,135 @@
+package cc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	revertedSHA = "4caf4b13ee373aca79c888b1d65a79c44ca70b5e"
+	otherSHA    = "23dd4dce65ec875e0f40718cb10169635cfbc004"
+)
+
+func TestRevertCC(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		template string
+		message  string
+		want     string
+	}{
+		{desc: "Conventional commit", message: "feat(aws)!: add profiles\n\nBody.\n\nRefs: PROJ-1", want: "feat(aws)!: add profiles"},
+		{desc: "Gitmoji", template: "gitmoji", message: "✨ feat: add profiles", want: "feat: add profiles"},
+		{desc: "Not a conventional commit", message: "Add profiles\n\nBody.", want: "Add profiles"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.Template = tC.template
+
+			got := cfg.revertCC(revertedSHA, tC.message).String()
+			want := "revert: " + tC.want + "\n\nThis reverts commit " + revertedSHA + ".\n\nRefs: 4caf4b1"
+			if got != want {
+				t.Errorf("got %q want %q", got, want)
+			}
+			if errs := cfg.lint(got); len(errs) != 0 {
+				t.Errorf("got lint errors %v", errs)
+			}
+		})
+	}
+}
+
+func TestRevert(t *testing.T) {
+	t.Run("Revert committed", func(t *testing.T) {
+		_, cli, ce := mockGitCLI(map[string]string{
+			"rev-parse --verify --quiet 4caf4b1^{commit}": revertedSHA,
+			"log -1 --format=%B " + revertedSHA:           "feat(aws): add profiles",
+		})
+		cli.yes = true
+
+		if err := cli.revert([]string{"4caf4b1"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if ce.command != "execute" {
+			t.Errorf("got command %q want the revert committed", ce.command)
+		}
+		if want := "revert: feat(aws): add profiles\n\nThis reverts commit " + revertedSHA + ".\n\nRefs: 4caf4b1"; cli.message != want {
+			t.Errorf("got %q want %q", cli.message, want)
+		}
+	})
+
+	t.Run("Stops when the revert is not committed", func(t *testing.T) {
+		buffer, cli, ce := mockGitCLI(map[string]string{
+			"rev-parse --verify --quiet 4caf4b1^{commit}": revertedSHA,
+			"rev-parse --verify --quiet 23dd4dc^{commit}": otherSHA,
+			"log -1 --format=%B " + revertedSHA:           "feat(aws): add profiles",
+			"log -1 --format=%B " + otherSHA:              "fix(ssh): close connections",
+			"rev-parse HEAD":                              "4191a16",
+		}, "n")
+
+		if err := cli.revert([]string{"4caf4b1", "23dd4dc"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if ce.command != "revert "+revertedSHA {
+			t.Errorf("got command %q want only the first commit reverted", ce.command)
+		}
+		if !strings.Contains(buffer.String(), "The revert of 4caf4b1 is staged") || strings.Contains(buffer.String(), "draft") {
+			t.Errorf("got %q want the revert staged and no draft saved", buffer.String())
+		}
+	})
+
+	t.Run("Staged changes refused", func(t *testing.T) {
+		_, cli, ce := mockGitCLI(map[string]string{"diff --cached --name-only": "main.go"})
+
+		if err := cli.revert([]string{"4caf4b1"}); err == nil {
+			t.Error("got no error")
+		}
+		if ce.command != "" {
+			t.Errorf("got command %q want nothing reverted", ce.command)
+		}
+	})
+}
+
+func TestCancelReverts(t *testing.T) {
+	revertOf := func(sha, reverted string) commit {
+		return commit{sha: sha, cc: &CC{Type: "revert", Subject: "feat: x", Body: "This reverts commit " + reverted + "."}}
+	}
+	feat := commit{sha: revertedSHA, cc: &CC{Type: "feat", Subject: "x"}}
+	fix := commit{sha: otherSHA, cc: &CC{Type: "fix", Subject: "y"}}
+
+	testCases := []struct {
+		desc    string
+		commits []commit
+		want    []commit
+	}{
+		{
+			desc:    "Reverted in the range",
+			commits: []commit{revertOf("aaaaaaa1", revertedSHA), fix, feat},
+			want:    []commit{fix},
+		},
+		{
+			desc:    "Reverted by an abbreviated SHA",
+			commits: []commit{revertOf("aaaaaaa1", "4caf4b1"), feat},
+			want:    nil,
+		},
+		{
+			desc:    "Reverted before the range",
+			commits: []commit{revertOf("aaaaaaa1", "0a0a0a0"), fix},
+			want:    []commit{revertOf("aaaaaaa1", "0a0a0a0"), fix},
+		},
+		{
+			desc:    "Revert reverted",
+			commits: []commit{revertOf("bbbbbbb2", "aaaaaaa1"), revertOf("aaaaaaa1", revertedSHA), feat},
+			want:    []commit{feat},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			if got := cancelReverts(tC.commits); !reflect.DeepEqual(got, tC.want) {
+				t.Errorf("got %v want %v", got, tC.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package cc
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxRememberedScopes is the number of scopes used before that are remembered.
+const maxRememberedScopes = 30
+
+// scopeRule maps the staged files matching Glob to Scope. A glob segment of **
+// matches any number of directories, and $1, $2, ... in Scope are replaced
+// with the path segments matched by the wildcard segments of Glob.
+type scopeRule struct {
+	Glob  string `yaml:"glob"`
+	Scope string `yaml:"scope"`
+}
+
+// matchGlob reports whether name matches the slash separated glob pattern,
+// returning the segments of name matched by wildcard segments of pattern.
+func matchGlob(pattern, name string) ([]string, bool) {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"), nil)
+}
+
+// matchSegments matches path segments against glob segments one at a time.
+func matchSegments(pattern, name, captures []string) ([]string, bool) {
+	if len(pattern) == 0 {
+		return captures, len(name) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if c, ok := matchSegments(pattern[1:], name[i:], captures); ok {
+				return c, true
+			}
+		}
+		return nil, false
+	}
+
+	if len(name) == 0 {
+		return nil, false
+	}
+
+	if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+		return nil, false
+	}
+
+	if strings.ContainsAny(pattern[0], "*?[") {
+		captures = append(captures, name[0])
+	}
+	return matchSegments(pattern[1:], name[1:], captures)
+}
+
+// scopeFor returns the scope for a file path using the first matching scope
+// rule. Without a matching rule, Go files are scoped to the name of their
+// package directory and other files to their top-level directory. Files at
+// the root of the repository have no scope.
+func (cfg *config) scopeFor(file string) string {
+	for _, r := range cfg.Scopes {
+		captures, ok := matchGlob(r.Glob, file)
+		if !ok {
+			continue
+		}
+
+		scope := r.Scope
+		for i := len(captures); i > 0; i-- {
+			scope = strings.ReplaceAll(scope, "$"+strconv.Itoa(i), captures[i-1])
+		}
+		return scope
+	}
+
+	dir := path.Dir(file)
+	if dir == "." {
+		return ""
+	}
+
+	if path.Ext(file) == ".go" {
+		return path.Base(dir)
+	}
+	return strings.Split(dir, "/")[0]
+}
+
+// stagedFiles returns the paths of the files staged for the next commit.
+func (c *CLI) stagedFiles() []string {
+	out, err := c.ce.output("diff", "--cached", "--name-only")
+	if err != nil || out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+// suggestScopes returns the sorted scopes of the staged files, or of the files
+// being committed when splitting.
+func (c *CLI) suggestScopes() []string {
+	files := c.paths
+	if files == nil {
+		files = c.stagedFiles()
+	}
+
+	seen := map[string]bool{}
+	var scopes []string
+
+	for _, f := range files {
+		scope := c.cfg.scopeFor(f)
+		if scope == "" || seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
+	}
+
+	sort.Strings(scopes)
+	return scopes
+}
+
+// scopesFile returns the file the scopes used before are remembered in, which is
+// inside the git directory of the current repository.
+func (c *CLI) scopesFile() (string, error) {
+	return c.ce.output("rev-parse", "--git-path", "cc/scopes")
+}
+
+// rememberedScopes returns the scopes used before, most recently used first.
+func (c *CLI) rememberedScopes() []string {
+	path, err := c.scopesFile()
+	if err != nil {
+		return nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var scopes []string
+	for _, s := range strings.Split(string(b), "\n") {
+		if s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// rememberScope moves the scope of the CC to the top of the scopes used before,
+// forgetting the least recently used ones past maxRememberedScopes. Failing to
+// remember it is not reported, as the commit was made.
+func (c *CLI) rememberScope() {
+	if c.cc.Scope == "" {
+		return
+	}
+
+	path, err := c.scopesFile()
+	if err != nil {
+		return
+	}
+
+	scopes := []string{c.cc.Scope}
+	for _, s := range c.rememberedScopes() {
+		if s != c.cc.Scope && len(scopes) < maxRememberedScopes {
+			scopes = append(scopes, s)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	os.WriteFile(path, []byte(strings.Join(scopes, "\n")+"\n"), 0644)
+}
+
+// scopeOptions returns the scopes offered in the picker: the suggestions for the
+// staged files, followed by the scopes used before. When the scopes are restricted,
+// only the allowed ones are offered, followed by the other allowed scopes and by
+// - to choose none.
+func (c *CLI) scopeOptions(suggestions []string) []option {
+	seen := map[string]bool{}
+	var options []option
+
+	add := func(scopes []string, description string) {
+		for _, s := range scopes {
+			if !seen[s] && c.cfg.isScope(s) {
+				seen[s] = true
+				options = append(options, option{s, description})
+			}
+		}
+	}
+
+	add(suggestions, "staged files")
+	add(c.rememberedScopes(), "used before")
+	if len(c.cfg.AllowedScopes) > 0 {
+		add(c.cfg.AllowedScopes, "allowed")
+		options = append(options, option{"-", "no scope"})
+	}
+	return options
+}
@@ -0,0 +1,102 @@
+package cc
+
+import (
+	"testing"
+)
+
+func TestScopeFor(t *testing.T) {
+	cfg := &config{Scopes: []scopeRule{
+		{Glob: "packages/*/**", Scope: "$1"},
+		{Glob: "services/*/cmd/*/**", Scope: "$1-$2"},
+		{Glob: "**/*.md", Scope: "docs"},
+		{Glob: "vendor/**", Scope: ""},
+	}}
+
+	testCases := []struct {
+		file string
+		want string
+	}{
+		{
+			file: "packages/api/src/index.ts",
+			want: "api",
+		},
+		{
+			file: "services/payments/cmd/worker/main.go",
+			want: "payments-worker",
+		},
+		{
+			file: "ssh/README.md",
+			want: "docs",
+		},
+		{
+			file: "README.md",
+			want: "docs",
+		},
+		{
+			file: "vendor/github.com/foo/foo.go",
+			want: "",
+		},
+		{
+			file: "ssh/ssh.go",
+			want: "ssh",
+		},
+		{
+			file: "internal/git/log.go",
+			want: "git",
+		},
+		{
+			file: ".github/workflows/test.yaml",
+			want: ".github",
+		},
+		{
+			file: "main.go",
+			want: "",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.file, func(t *testing.T) {
+			got := cfg.scopeFor(tC.file)
+
+			if got != tC.want {
+				t.Errorf("got %q want %q", got, tC.want)
+			}
+		})
+	}
+}
+
+func TestScopeSuggestions(t *testing.T) {
+	staged := "ssh/ssh.go\naws/cmd.go\nssh/cmd.go\nmain.go\ncc/cc.go"
+
+	t.Run("Suggestion picked by number", func(t *testing.T) {
+		buffer, cli, _ := mockGitCLI(map[string]string{"diff --cached --name-only": staged}, "1")
+
+		cli.readScope()
+
+		promptGot := buffer.String()
+		promptWant := "0.  \033[36;1maws\033[0m\n1.  \033[36;1mcc\033[0m\n2.  \033[36;1mssh\033[0m\n" +
+			"\nEnter a scope or a number between 0 and 2: "
+		if promptGot != promptWant {
+			t.Errorf("got %q want %q", promptGot, promptWant)
+		}
+
+		got := cli.cc.Scope
+		want := "cc"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("Suggestion overridden", func(t *testing.T) {
+		_, cli, _ := mockGitCLI(map[string]string{"diff --cached --name-only": staged}, "compcmd")
+
+		cli.readScope()
+
+		got := cli.cc.Scope
+		want := "compcmd"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package cc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// fileGroup is the staged files that share a scope, which are committed together
+// when the staged changes are split.
+type fileGroup struct {
+	scope string
+	files []string
+}
+
+// groupByScope groups files by the scope of the first scopes rule that matches
+// them, sorted by scope with the files that have no scope last.
+func (cfg *config) groupByScope(files []string) []fileGroup {
+	var groups []fileGroup
+	index := map[string]int{}
+
+	for _, f := range files {
+		scope := cfg.scopeFor(f)
+		i, ok := index[scope]
+		if !ok {
+			i = len(groups)
+			index[scope] = i
+			groups = append(groups, fileGroup{scope: scope})
+		}
+		groups[i].files = append(groups[i].files, f)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].scope == "" || groups[j].scope == "" {
+			return groups[j].scope == ""
+		}
+		return groups[i].scope < groups[j].scope
+	})
+	return groups
+}
+
+// partiallyStaged returns the staged files that also have unstaged changes, which
+// cannot be split since git commit -- <paths> commits the files as they are in the
+// work tree.
+func (c *CLI) partiallyStaged(staged []string) ([]string, error) {
+	out, err := c.ce.output("diff", "--name-only", "--no-renames")
+	if err != nil {
+		return nil, err
+	}
+
+	unstaged := map[string]bool{}
+	for _, f := range strings.Split(out, "\n") {
+		unstaged[f] = true
+	}
+
+	var partial []string
+	for _, f := range staged {
+		if unstaged[f] {
+			partial = append(partial, f)
+		}
+	}
+	return partial, nil
+}
+
+// split makes a conventional commit for each group of staged files that share a
+// scope, prompting for the fields of each one with its scope pre-filled. A group
+// whose commit is not confirmed stays staged, and when the prompts are given up,
+// such as when no type is chosen, the commits already made are kept and the
+// remaining groups stay staged. When git fails to make a commit, the commits made
+// before it are undone with their changes staged again, so that the index is as
+// it was before splitting.
+func (c *CLI) split() error {
+	out, err := c.ce.output("diff", "--cached", "--name-only", "--no-renames")
+	if err != nil || out == "" {
+		fmt.Fprintln(c.Out, "Nothing is staged to split")
+		return &CCError{"Nothing staged"}
+	}
+	staged := strings.Split(out, "\n")
+
+	partial, err := c.partiallyStaged(staged)
+	if err != nil {
+		fmt.Fprintf(c.Out, "Error finding unstaged changes: %s\n", err)
+		return err
+	}
+	if len(partial) > 0 {
+		fmt.Fprintf(c.Out, "Files with unstaged changes cannot be split, stage or stash their changes first:\n  %s\n", strings.Join(partial, "\n  "))
+		return &CCError{"Partially staged files"}
+	}
+
+	head, err := c.ce.output("rev-parse", "--verify", "HEAD")
+	if err != nil {
+		fmt.Fprintln(c.Out, "Splitting needs a commit to start from, make the first commit with z cc")
+		return &CCError{"No commits"}
+	}
+
+	groups := c.cfg.groupByScope(staged)
+	for i, g := range groups {
+		scope := g.scope
+		if scope == "" {
+			scope = "no scope"
+		}
+		fmt.Fprintf(c.Out, "\nCommit %d of %d, %s:\n  %s\n\n", i+1, len(groups), scope, strings.Join(g.files, "\n  "))
+
+		c.cc = &CC{}
+		c.paths = g.files
+
+		c.readBranch()
+		c.cc.Scope = g.scope
+		c.readCoauthors()
+		// the co-authors are chosen for the first commit, and remembered for the others
+		c.pair, c.solo = false, false
+
+		if err := c.readMissingFields(); err != nil {
+			fmt.Fprintf(c.Out, "Stopped splitting at commit %d of %d, the files of the commits not made are still staged\n", i+1, len(groups))
+			return err
+		}
+		c.buildMessage()
+		if err := c.makeCommit(); err != nil {
+			c.restoreStaged(head)
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreStaged undoes the commits made since head, keeping their changes staged,
+// which is a no-op when none were made.
+func (c *CLI) restoreStaged(head string) {
+	if _, err := c.ce.output("reset", "--soft", head); err != nil {
+		fmt.Fprintf(c.Out, "Error undoing the commits made, reset to %s to undo them: %s\n", shortSHA(head), err)
+		return
+	}
+	fmt.Fprintf(c.Out, "Undid the commits made since %s, the index is as it was before splitting\n", shortSHA(head))
+}
@@ -0,0 +1,111 @@
+package cc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// splitOutputs are the git outputs of files staged under ssh, aws and the root of
+// the repository, with another file changed but not staged.
+var splitOutputs = map[string]string{
+	"diff --cached --name-only --no-renames": "ssh/ssh.go\nREADME.md\naws/aws.go\nssh/README.md",
+	"diff --name-only --no-renames":          "main.go",
+	"rev-parse --verify HEAD":                "1a2b3c4d5e6f",
+	"reset --soft 1a2b3c4d5e6f":              "",
+}
+
+// splitInputs are the type, scope, subject, body, footer and confirmation of a
+// commit for each type and subject.
+func splitInputs(typesAndSubjects ...string) []string {
+	var inputs []string
+	for i := 0; i+1 < len(typesAndSubjects); i += 2 {
+		inputs = append(inputs, typesAndSubjects[i], "", typesAndSubjects[i+1], "", "", "y")
+	}
+	return inputs
+}
+
+func TestGroupByScope(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Scopes = []scopeRule{{Glob: "**/*.md", Scope: "docs"}}
+
+	got := cfg.groupByScope([]string{"go.mod", "ssh/ssh.go", "README.md", "aws/aws.go", "ssh/README.md"})
+	want := []fileGroup{
+		{scope: "aws", files: []string{"aws/aws.go"}},
+		{scope: "docs", files: []string{"README.md", "ssh/README.md"}},
+		{scope: "ssh", files: []string{"ssh/ssh.go"}},
+		{scope: "", files: []string{"go.mod"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		inputs   []string
+		unstaged string
+		failing  string
+		commits  []string
+		err      bool
+		output   string
+		absent   string
+	}{
+		{
+			desc:    "Commit per scope",
+			inputs:  splitInputs("3", "add profiles", "4", "close connections", "2", "describe split"),
+			commits: []string{"aws/aws.go", "ssh/ssh.go ssh/README.md", "README.md"},
+			output:  "docs: describe split",
+		},
+		{
+			desc:    "Scope pre-filled",
+			inputs:  splitInputs("3", "add profiles"),
+			commits: []string{"aws/aws.go"},
+			err:     true,
+			output:  "Enter a scope or a number between 0 and 0 [aws]: ",
+		},
+		{
+			desc:     "Partially staged files refused",
+			unstaged: "ssh/ssh.go\nmain.go",
+			err:      true,
+			output:   "\n  ssh/ssh.go\n",
+		},
+		{
+			desc:    "Commits kept when the prompts are given up",
+			inputs:  append(splitInputs("3", "add profiles"), "x", "x", "x"),
+			commits: []string{"aws/aws.go"},
+			err:     true,
+			output:  "Stopped splitting at commit 2 of 3",
+			absent:  "Undid",
+		},
+		{
+			desc:    "Commits undone when one fails",
+			inputs:  splitInputs("3", "add profiles", "4", "close connections"),
+			failing: "ssh/ssh.go ssh/README.md",
+			commits: []string{"aws/aws.go"},
+			err:     true,
+			output:  "Undid the commits made since 1a2b3c4",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			buffer, cli, ce := mockGitCLI(splitOutputs, tC.inputs...)
+			if tC.unstaged != "" {
+				ce.outputs["diff --name-only --no-renames"] = tC.unstaged
+			}
+			ce.failing = tC.failing
+
+			if err := cli.split(); (err != nil) != tC.err {
+				t.Fatalf("got error %v", err)
+			}
+
+			if !reflect.DeepEqual(ce.commits, tC.commits) {
+				t.Errorf("got commits %v want %v", ce.commits, tC.commits)
+			}
+			if got := buffer.String(); !strings.Contains(got, tC.output) || tC.absent != "" && strings.Contains(got, tC.absent) {
+				t.Errorf("got %q want %q", got, tC.output)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package cc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// styleConfig holds the style rules checked before a commit is made, following
+// the writing guidelines. A length of 0 turns its rule off.
+type styleConfig struct {
+	HeaderMaxLength  int  `yaml:"headerMaxLength"`
+	NoTrailingPeriod bool `yaml:"noTrailingPeriod"`
+	LowercaseSubject bool `yaml:"lowercaseSubject"`
+	Imperative       bool `yaml:"imperative"`
+	BodyWrap         int  `yaml:"bodyWrap"`
+}
+
+// defaultStyle turns on every style rule, with the 72 columns git tooling expects.
+var defaultStyle = styleConfig{
+	HeaderMaxLength:  72,
+	NoTrailingPeriod: true,
+	LowercaseSubject: true,
+	Imperative:       true,
+	BodyWrap:         72,
+}
+
+// imperatives maps the past tense and third person forms of verbs that commonly
+// start a subject to their imperative form.
+var imperatives = map[string]string{
+	"added": "add", "adds": "add",
+	"allowed": "allow", "allows": "allow",
+	"bumped": "bump", "bumps": "bump",
+	"changed": "change", "changes": "change",
+	"cleaned": "clean", "cleans": "clean",
+	"converted": "convert", "converts": "convert",
+	"created": "create", "creates": "create",
+	"deleted": "delete", "deletes": "delete",
+	"deprecated": "deprecate", "deprecates": "deprecate",
+	"disabled": "disable", "disables": "disable",
+	"documented": "document", "documents": "document",
+	"dropped": "drop", "drops": "drop",
+	"enabled": "enable", "enables": "enable",
+	"ensured": "ensure", "ensures": "ensure",
+	"extracted": "extract", "extracts": "extract",
+	"fixed": "fix", "fixes": "fix",
+	"handled": "handle", "handles": "handle",
+	"implemented": "implement", "implements": "implement",
+	"improved": "improve", "improves": "improve",
+	"introduced": "introduce", "introduces": "introduce",
+	"made": "make", "makes": "make",
+	"merged": "merge", "merges": "merge",
+	"moved": "move", "moves": "move",
+	"optimized": "optimize", "optimizes": "optimize",
+	"prevented": "prevent", "prevents": "prevent",
+	"refactored": "refactor", "refactors": "refactor",
+	"removed": "remove", "removes": "remove",
+	"renamed": "rename", "renames": "rename",
+	"replaced": "replace", "replaces": "replace",
+	"restored": "restore", "restores": "restore",
+	"reverted": "revert", "reverts": "revert",
+	"reworked": "rework", "reworks": "rework",
+	"simplified": "simplify", "simplifies": "simplify",
+	"supported": "support", "supports": "support",
+	"tested": "test", "tests": "test",
+	"updated": "update", "updates": "update",
+	"upgraded": "upgrade", "upgrades": "upgrade",
+	"used": "use", "uses": "use",
+	"wrote": "write", "writes": "write",
+}
+
+// listMarker matches the marker of a list item, which wrapped lines are indented past.
+var listMarker = regexp.MustCompile(`^([-*+]|[0-9]+[.)]) `)
+
+// styleViolation is a style rule broken by a conventional commit, along with a fix
+// that changes the CC to follow it, when it can be fixed automatically.
+type styleViolation struct {
+	rule    string
+	message string
+	fix     func(cc *CC)
+}
+
+// check returns the style rules broken by cc, whose message has header as its
+// first line.
+func (s *styleConfig) check(cc CC, header string) []styleViolation {
+	var violations []styleViolation
+
+	if n := utf8.RuneCountInString(header); s.HeaderMaxLength > 0 && n > s.HeaderMaxLength {
+		violations = append(violations, styleViolation{
+			rule:    "header-max-length",
+			message: fmt.Sprintf("header is %d characters long, the maximum is %d", n, s.HeaderMaxLength),
+		})
+	}
+
+	if s.NoTrailingPeriod && strings.HasSuffix(cc.Subject, ".") {
+		violations = append(violations, styleViolation{
+			rule:    "subject-full-stop",
+			message: "subject must not end with a period",
+			fix:     func(cc *CC) { cc.Subject = strings.TrimRight(cc.Subject, ".") },
+		})
+	}
+
+	word, _, _ := strings.Cut(cc.Subject, " ")
+
+	if s.LowercaseSubject && isCapitalized(word) {
+		violations = append(violations, styleViolation{
+			rule:    "subject-case",
+			message: "subject must not start with a capital letter",
+			fix: func(cc *CC) {
+				r, n := utf8.DecodeRuneInString(cc.Subject)
+				cc.Subject = string(unicode.ToLower(r)) + cc.Subject[n:]
+			},
+		})
+	}
+
+	if imperative, ok := imperatives[strings.ToLower(word)]; s.Imperative && ok {
+		violations = append(violations, styleViolation{
+			rule:    "subject-imperative",
+			message: fmt.Sprintf("subject must use the imperative mood: %q not %q", imperative, strings.ToLower(word)),
+			fix:     fixImperative,
+		})
+	}
+
+	if n := countLongLines(cc.Body, s.BodyWrap); s.BodyWrap > 0 && n > 0 {
+		v := styleViolation{
+			rule:    "body-max-line-length",
+			message: fmt.Sprintf("body has %d lines longer than %d characters", n, s.BodyWrap),
+		}
+		if wrapped := wrapBody(cc.Body, s.BodyWrap); wrapped != cc.Body {
+			width := s.BodyWrap
+			v.fix = func(cc *CC) { cc.Body = wrapBody(cc.Body, width) }
+		}
+		violations = append(violations, v)
+	}
+
+	return violations
+}
+
+// isCapitalized reports whether word starts with a capital letter followed by
+// lowercase letters only, so that names such as API and GitHub are left alone.
+func isCapitalized(word string) bool {
+	r, n := utf8.DecodeRuneInString(word)
+	if !unicode.IsUpper(r) {
+		return false
+	}
+	for _, r := range word[n:] {
+		if unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// fixImperative replaces the first word of the subject with its imperative form,
+// keeping whether it was capitalized.
+func fixImperative(cc *CC) {
+	word, rest, _ := strings.Cut(cc.Subject, " ")
+	imperative, ok := imperatives[strings.ToLower(word)]
+	if !ok {
+		return
+	}
+
+	if isCapitalized(word) {
+		imperative = strings.ToUpper(imperative[:1]) + imperative[1:]
+	}
+	cc.Subject = strings.TrimSpace(imperative + " " + rest)
+}
+
+// isPreformatted reports whether a body line is part of a code block, which is not
+// wrapped, and whether it opens or closes a fenced one.
+func isPreformatted(line string, fenced bool) (bool, bool) {
+	if strings.HasPrefix(strings.TrimSpace(line), "```") {
+		return true, !fenced
+	}
+	return fenced || strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t"), fenced
+}
+
+// countLongLines returns the number of lines of body longer than width, outside
+// of code blocks.
+func countLongLines(body string, width int) int {
+	n := 0
+	fenced := false
+	for _, line := range strings.Split(body, "\n") {
+		var pre bool
+		if pre, fenced = isPreformatted(line, fenced); !pre && utf8.RuneCountInString(line) > width {
+			n++
+		}
+	}
+	return n
+}
+
+// wrapBody wraps the lines of body longer than width on spaces, leaving code
+// blocks and words longer than width as they are.
+func wrapBody(body string, width int) string {
+	var lines []string
+	fenced := false
+	for _, line := range strings.Split(body, "\n") {
+		var pre bool
+		if pre, fenced = isPreformatted(line, fenced); pre || utf8.RuneCountInString(line) <= width {
+			lines = append(lines, line)
+			continue
+		}
+		lines = append(lines, wrapLine(line, width)...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapLine wraps a line at width on spaces. Continuation lines are indented like the
+// line, and past its marker when it is a list item.
+func wrapLine(line string, width int) []string {
+	text := strings.TrimLeft(line, " ")
+	indent := line[:len(line)-len(text)]
+	continuation := indent + strings.Repeat(" ", len(listMarker.FindString(text)))
+
+	var lines []string
+	prefix, current := indent, ""
+	for _, word := range strings.Fields(text) {
+		if current != "" && utf8.RuneCountInString(prefix+current)+1+utf8.RuneCountInString(word) > width {
+			lines = append(lines, prefix+current)
+			prefix, current = continuation, ""
+		}
+		if current != "" {
+			current += " "
+		}
+		current += word
+	}
+	return append(lines, prefix+current)
+}
+
+// reviewStyle writes the style rules the message breaks, and offers to fix the
+// ones that can be fixed automatically before rebuilding the message. Nothing is
+// changed without asking, so with the yes parameter the rules are only written.
+func (c *CLI) reviewStyle() {
+	header, _, _ := strings.Cut(c.message, "\n")
+	violations := c.cfg.Style.check(*c.cc, header)
+	if len(violations) == 0 {
+		return
+	}
+
+	fixable := 0
+	fmt.Fprintln(c.Out, "\nThe message does not follow the writing guidelines:")
+	for _, v := range violations {
+		if v.fix != nil {
+			fixable++
+			fmt.Fprintf(c.Out, "  %s, can be fixed [%s]\n", v.message, v.rule)
+		} else {
+			fmt.Fprintf(c.Out, "  %s [%s]\n", v.message, v.rule)
+		}
+	}
+
+	if fixable == 0 || c.yes {
+		return
+	}
+
+	fmt.Fprint(c.Out, "Fix them automatically [Y/n]: ")
+	if input := strings.ToLower(c.readLine()); input != "" && input != "y" && input != "yes" {
+		return
+	}
+
+	for _, v := range violations {
+		if v.fix != nil {
+			v.fix(c.cc)
+		}
+	}
+	c.buildMessage()
+}
@@ -0,0 +1,128 @@
+package cc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStyleCheck(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		cc    CC
+		rules []string
+	}{
+		{
+			desc: "Following the guidelines",
+			cc:   CC{Type: "feat", Subject: "add style rules", Body: "Wrapped at 72 columns."},
+		},
+		{
+			desc:  "Capitalized past tense with a period",
+			cc:    CC{Type: "fix", Subject: "Fixed the wrapping."},
+			rules: []string{"subject-full-stop", "subject-case", "subject-imperative"},
+		},
+		{
+			desc: "Names left alone",
+			cc:   CC{Type: "docs", Subject: "GitHub API changes are described"},
+		},
+		{
+			desc:  "Long header and body",
+			cc:    CC{Type: "feat", Subject: strings.Repeat("long ", 15), Body: strings.Repeat("word ", 20)},
+			rules: []string{"header-max-length", "body-max-line-length"},
+		},
+		{
+			desc: "Code blocks not wrapped",
+			cc:   CC{Type: "docs", Subject: "show usage", Body: "Run:\n\n    " + strings.Repeat("x ", 40) + "\n```\n" + strings.Repeat("y ", 40) + "\n```"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			header, _, _ := strings.Cut(tC.cc.String(), "\n")
+
+			var got []string
+			for _, v := range defaultStyle.check(tC.cc, header) {
+				got = append(got, v.rule)
+			}
+
+			if !reflect.DeepEqual(got, tC.rules) {
+				t.Errorf("got %q want %q", got, tC.rules)
+			}
+		})
+	}
+}
+
+func TestWrapBody(t *testing.T) {
+	body := "Short line.\n\n- a list item that is long enough to need wrapping past the marker of the item\n" + strings.Repeat("x", 30)
+
+	got := wrapBody(body, 40)
+	want := "Short line.\n\n- a list item that is long enough to\n  need wrapping past the marker of the\n  item\n" + strings.Repeat("x", 30)
+
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	if countLongLines(got, 40) != 0 {
+		t.Errorf("got long lines left in %q", got)
+	}
+}
+
+func TestReviewStyle(t *testing.T) {
+	t.Run("Violations fixed", func(t *testing.T) {
+		buffer, cli, _ := mockCLI("y")
+		cli.cc = &CC{Type: "fix", Subject: "Updated the picker."}
+		cli.buildMessage()
+
+		cli.reviewStyle()
+
+		if want := "fix: update the picker"; cli.message != want {
+			t.Errorf("got %q want %q", cli.message, want)
+		}
+
+		want := "\nThe message does not follow the writing guidelines:\n" +
+			"  subject must not end with a period, can be fixed [subject-full-stop]\n" +
+			"  subject must not start with a capital letter, can be fixed [subject-case]\n" +
+			"  subject must use the imperative mood: \"update\" not \"updated\", can be fixed [subject-imperative]\n" +
+			"Fix them automatically [Y/n]: "
+		if got := buffer.String(); got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("Fixes declined", func(t *testing.T) {
+		_, cli, _ := mockCLI("n")
+		cli.cc = &CC{Type: "fix", Subject: "update the picker."}
+		cli.buildMessage()
+
+		cli.reviewStyle()
+
+		if want := "fix: update the picker."; cli.message != want {
+			t.Errorf("got %q want %q", cli.message, want)
+		}
+	})
+
+	t.Run("Only written with yes", func(t *testing.T) {
+		buffer, cli, _ := mockCLI()
+		cli.yes = true
+		cli.cc = &CC{Type: "fix", Subject: "update the picker."}
+		cli.buildMessage()
+
+		cli.reviewStyle()
+
+		if strings.Contains(buffer.String(), "[Y/n]") || cli.message != "fix: update the picker." {
+			t.Errorf("got %q and message %q want the violation only written", buffer.String(), cli.message)
+		}
+	})
+
+	t.Run("Rules turned off", func(t *testing.T) {
+		buffer, cli, _ := mockCLI()
+		cli.cfg.Style = styleConfig{}
+		cli.cc = &CC{Type: "fix", Subject: "Updated the picker."}
+		cli.buildMessage()
+
+		cli.reviewStyle()
+
+		if buffer.Len() != 0 {
+			t.Errorf("got %q want nothing written", buffer.String())
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package cc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// conventionalTemplate lays out a message the way the CC String method does.
+const conventionalTemplate = `{{.Type}}{{with .Scope}}({{.}}){{end}}{{if .Breaking}}!{{end}}: {{.Subject}}
+{{- with .Body}}
+
+{{.}}{{end}}
+{{- with .Footers}}
+
+{{range .}}{{.}}
+{{end}}{{end}}`
+
+// templatePresets are the built-in templates that can be named by the template
+// config instead of writing one. Angular marks breaking changes only with the
+// BREAKING CHANGE footer, and gitmoji starts the header with the emoji of the type.
+var templatePresets = map[string]string{
+	"conventional": conventionalTemplate,
+	"angular":      strings.Replace(conventionalTemplate, "{{if .Breaking}}!{{end}}", "", 1),
+	"gitmoji":      "{{with gitmoji .Type}}{{.}} {{end}}" + conventionalTemplate,
+}
+
+// gitmojis are the emojis of https://gitmoji.dev for the well known types.
+var gitmojis = map[string]string{
+	"build":    "📦️",
+	"chore":    "🔧",
+	"ci":       "👷",
+	"docs":     "📝",
+	"feat":     "✨",
+	"fix":      "🐛",
+	"perf":     "⚡️",
+	"refactor": "♻️",
+	"revert":   "⏪️",
+	"style":    "🎨",
+	"test":     "✅",
+}
+
+// templateSamples are the commits a template is rendered with when it is loaded, to
+// check that the messages it lays out can be read back as conventional commits.
+var templateSamples = []CC{
+	{Type: "feat", Subject: "check the template"},
+	{Type: "fix", Scope: "cc", Breaking: true, Subject: "read templated messages", Body: "Body.", Footers: []Trailer{{"Refs", ": ", "PROJ-1"}}},
+}
+
+// templateFuncs are the functions that can be called from a template.
+var templateFuncs = template.FuncMap{
+	"gitmoji": func(cctype string) string { return gitmojis[cctype] },
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+}
+
+// templateData is the data a template is rendered with: the fields of the CC, and
+// a Footer method that returns the value of the footer with the given token, such
+// as {{.Footer "Refs"}}.
+type templateData struct {
+	CC
+}
+
+// Footer returns the value of the first footer with the token, ignoring case, or
+// an empty string when there is none.
+func (d templateData) Footer(token string) string {
+	for _, t := range d.Footers {
+		if strings.EqualFold(t.Token, token) {
+			return t.Value
+		}
+	}
+	return ""
+}
+
+// parseTemplate returns the template named by the template config, which is the
+// name of a preset or the text of a template. No template means the conventional
+// commit layout of the CC String method. A template is refused when the messages it
+// lays out cannot be parsed back into commits of the same type, as lint, check and
+// changelog would then reject or skip the commits made with it.
+func (cfg *config) parseTemplate() (*template.Template, error) {
+	if cfg.Template == "" {
+		return nil, nil
+	}
+
+	text, ok := templatePresets[cfg.Template]
+	if !ok {
+		text = cfg.Template
+	}
+	tmpl, err := template.New("message").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sample := range templateSamples {
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, templateData{sample}); err != nil {
+			return nil, err
+		}
+
+		message := strings.TrimSpace(b.String())
+		if cc, err := Parse(cfg.untemplate(message)); err != nil || cc.Type != sample.Type {
+			header, _, _ := strings.Cut(message, "\n")
+			return nil, fmt.Errorf("%q is not a conventional commit header of type %s", header, sample.Type)
+		}
+	}
+	return tmpl, nil
+}
+
+// render lays out the commit message of cc with the configured template, falling
+// back to the conventional commit layout when rendering fails.
+func (c *CLI) render(cc CC) string {
+	if c.cfg.tmpl == nil {
+		return cc.String()
+	}
+
+	var b bytes.Buffer
+	if err := c.cfg.tmpl.Execute(&b, templateData{cc}); err != nil {
+		fmt.Fprintf(c.Out, "Error rendering template, using the conventional commit format: %s\n", err)
+		return cc.String()
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// untemplate returns a commit message without the emoji the gitmoji preset, or a
+// template calling the gitmoji function, adds to the header, so that the message
+// can be parsed as a conventional commit.
+func (cfg *config) untemplate(message string) string {
+	if !strings.Contains(cfg.Template, "gitmoji") {
+		return message
+	}
+	return stripGitmoji(message)
+}
+
+// stripGitmoji removes the emoji the gitmoji preset starts the header with, so
+// that the rest of the message can be checked as a conventional commit. Emojis
+// are matched with or without the variation selector some of them end with.
+func stripGitmoji(message string) string {
+	lines := strings.Split(message, "\n")
+	for i, line := range lines {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		for _, emoji := range gitmojis {
+			rest, ok := strings.CutPrefix(line, strings.TrimSuffix(emoji, "\uFE0F"))
+			if !ok {
+				continue
+			}
+			if rest, ok = strings.CutPrefix(strings.TrimPrefix(rest, "\uFE0F"), " "); ok {
+				lines[i] = rest
+				return strings.Join(lines, "\n")
+			}
+		}
+		break
+	}
+	return message
+}
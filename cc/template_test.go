@@ -0,0 +1,107 @@
+package cc
+
+import (
+	"testing"
+)
+
+// templateCCs are conventional commits with and without the optional fields.
+var templateCCs = []CC{
+	{Type: "feat", Subject: "add templates"},
+	{Type: "fix", Scope: "cc", Breaking: true, Subject: "render footers", Body: "First.\n\nSecond."},
+	{Type: "docs", Subject: "describe presets", Footers: []Trailer{{"Refs", ": ", "PROJ-1"}, {"Closes", " ", "#2"}}},
+}
+
+func TestTemplate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		template string
+		cc       CC
+		want     string
+		lint     bool
+	}{
+		{desc: "Conventional preset", template: "conventional", cc: templateCCs[0], want: templateCCs[0].String()},
+		{desc: "Conventional preset with a body", template: "conventional", cc: templateCCs[1], want: templateCCs[1].String()},
+		{desc: "Conventional preset with footers", template: "conventional", cc: templateCCs[2], want: templateCCs[2].String()},
+		{desc: "Angular preset", template: "angular", cc: templateCCs[1], want: "fix(cc): render footers\n\nFirst.\n\nSecond."},
+		{desc: "Gitmoji preset", template: "gitmoji", cc: templateCCs[0], want: "✨ feat: add templates", lint: true},
+		{desc: "Gitmoji preset without an emoji", template: "gitmoji", cc: CC{Type: "deps", Subject: "update"}, want: "deps: update"},
+		{
+			desc:     "Custom template with a ticket in the subject",
+			template: `{{.Type}}: {{with .Footer "refs"}}[{{.}}] {{end}}{{.Subject}}`,
+			cc:       templateCCs[2],
+			want:     "docs: [PROJ-1] describe presets",
+		},
+		{
+			desc:     "Custom template with gitmoji",
+			template: `{{gitmoji .Type}} {{.Type}}: {{.Subject}}`,
+			cc:       templateCCs[0],
+			want:     "✨ feat: add templates",
+			lint:     true,
+		},
+		{
+			desc:     "Failing template falls back to String",
+			template: `{{.Type}}: {{.Subject}}{{if eq .Subject "add templates"}}{{.Missing}}{{end}}`,
+			cc:       templateCCs[0],
+			want:     templateCCs[0].String(),
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			_, cli, _ := mockCLI()
+			cli.cfg.Template = tC.template
+			tmpl, err := cli.cfg.parseTemplate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			cli.cfg.tmpl = tmpl
+			cli.cc = &tC.cc
+
+			cli.buildMessage()
+
+			if cli.message != tC.want {
+				t.Errorf("got %q want %q", cli.message, tC.want)
+			}
+			if errs := cli.cfg.lint(cli.message); tC.lint && len(errs) != 0 {
+				t.Errorf("got errors %v want the message accepted", errs)
+			}
+		})
+	}
+
+	t.Run("Template refused when its messages cannot be read back", func(t *testing.T) {
+		for _, template := range []string{`[{{.Type}}] {{.Subject}}`, `{{.Subject}}`, `{{.Missing}}`, `{{upper .Type}}: {{.Subject}}`} {
+			cfg := defaultConfig()
+			cfg.Template = template
+
+			if _, err := cfg.parseTemplate(); err == nil {
+				t.Errorf("%s got no error", template)
+			}
+		}
+	})
+
+	t.Run("Invalid template reported when loading config", func(t *testing.T) {
+		buffer, cli, _ := mockGitCLI(map[string]string{
+			"rev-parse --show-toplevel": writeFiles(t, map[string]string{repoConfigFile: "template: \"{{.Type\"\n"}),
+		})
+
+		cli.loadConfig()
+
+		if cli.cfg.tmpl != nil || buffer.Len() == 0 {
+			t.Errorf("got template %v and output %q want an error", cli.cfg.tmpl, buffer.String())
+		}
+	})
+}
+
+func TestGitmojiLint(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Template = "gitmoji"
+
+	for _, message := range []string{"✨ feat: add templates", "♻ refactor(cc): split render", "# comment\n🐛 fix: strip emoji"} {
+		if errs := cfg.lint(message); len(errs) != 0 {
+			t.Errorf("%q got errors %v", message, errs)
+		}
+	}
+
+	if errs := defaultConfig().lint("✨ feat: add templates"); len(errs) == 0 {
+		t.Errorf("got no errors without the gitmoji preset")
+	}
+}